@@ -0,0 +1,252 @@
+package basestring
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"unicode"
+)
+
+/// Alphabet defines the set of symbols a BaseString can hold, and how they
+/// are encoded. Symbols are numbered from 1 (zero is never valid) and packed
+/// using the fewest bits that can hold the largest code.
+type Alphabet struct {
+	name    string
+	symbols []rune
+	ascii   [128]byte
+	other   map[rune]byte
+	width   uint
+
+	// masks maps each code onto the set of nucleotides it stands for. It is
+	// nil for alphabets that aren't nucleic acids.
+	masks []byte
+}
+
+/// AlphabetError describes a problem defining an alphabet
+type AlphabetError string
+
+func (self AlphabetError) Error() string {
+	return fmt.Sprintf("Invalid alphabet: %s", string(self))
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+const (
+	maskA = 1 << iota
+	maskC
+	maskG
+	maskT
+)
+
+/// nucleicMasks maps a nucleic acid code onto the set of unambiguous bases it
+/// stands for, expressed as a bitmask of maskA, maskC, maskG & maskT.
+var nucleicMasks = []byte{
+	0,
+	maskG,
+	maskA,
+	maskT,
+	maskC,
+	maskA | maskC | maskG | maskT, // N
+	maskA | maskG,                 // R
+	maskC | maskT,                 // Y
+	maskG | maskT,                 // K
+	maskA | maskC,                 // M
+	maskC | maskG,                 // S
+	maskA | maskT,                 // W
+	maskC | maskG | maskT,         // B
+	maskA | maskG | maskT,         // D
+	maskA | maskC | maskT,         // H
+	maskA | maskC | maskG,         // V
+}
+
+/// The built-in alphabets. DNA and RNA share their encoding, with U taking
+/// the place of T, so a string can be converted between them without
+/// re-encoding. The four unambiguous bases take the values 1-4 and the IUPAC
+/// ambiguity codes fill the rest of the nibble.
+var (
+	DNA     = newNucleicAlphabet("DNA", "GATCNRYKMSWBDHV")
+	RNA     = newNucleicAlphabet("RNA", "GAUCNRYKMSWBDHV")
+	Protein = mustNewAlphabet("Protein", "ACDEFGHIKLMNPQRSTVWYX*")
+)
+
+/// NewAlphabet creates a custom alphabet from a string of distinct symbols.
+/// Symbols are encoded in the order given.
+func NewAlphabet(name, symbols string) (*Alphabet, error) {
+	result := &Alphabet{
+		name:    name,
+		symbols: []rune{'\x00'},
+		other:   map[rune]byte{},
+	}
+
+	for _, c := range symbols {
+		if c == '\x00' {
+			return nil, AlphabetError("NUL is not a valid symbol")
+		}
+		if result.contains(c) {
+			return nil, AlphabetError(fmt.Sprintf("duplicate symbol %c", c))
+		}
+		if len(result.symbols) > 255 {
+			return nil, AlphabetError("too many symbols")
+		}
+
+		code := byte(len(result.symbols))
+		result.symbols = append(result.symbols, c)
+		if c < 128 {
+			result.ascii[c] = code
+		} else {
+			result.other[c] = code
+		}
+	}
+
+	if len(result.symbols) == 1 {
+		return nil, AlphabetError("no symbols")
+	}
+
+	result.width = uint(bits.Len(uint(len(result.symbols) - 1)))
+	return result, nil
+}
+
+func mustNewAlphabet(name, symbols string) *Alphabet {
+	result, err := NewAlphabet(name, symbols)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func newNucleicAlphabet(name, symbols string) *Alphabet {
+	result := mustNewAlphabet(name, symbols)
+	result.masks = nucleicMasks
+	return result
+}
+
+/// Name returns the name of the alphabet
+func (self *Alphabet) Name() string {
+	return self.name
+}
+
+/// Symbols returns the symbols in the alphabet, in encoding order
+func (self *Alphabet) Symbols() string {
+	return string(self.symbols[1:])
+}
+
+/// BitsPerSymbol returns the number of bits used to pack each symbol
+func (self *Alphabet) BitsPerSymbol() int {
+	return int(self.width)
+}
+
+/// Matches checks whether two symbols could stand for the same thing. For
+/// nucleic acid alphabets ambiguity codes are treated as the set of bases
+/// they represent, so 'N' matches anything and 'R' matches both 'A' and 'G'.
+/// Otherwise symbols only match themselves. Invalid symbols never match.
+func (self *Alphabet) Matches(a, b rune) bool {
+	x, err := self.encode(a)
+	if err != nil {
+		return false
+	}
+	y, err := self.encode(b)
+	if err != nil {
+		return false
+	}
+	return self.codesMatch(x, y)
+}
+
+func (self *Alphabet) String() string {
+	return self.name
+}
+
+func (self *Alphabet) contains(c rune) bool {
+	_, err := self.encode(c)
+	return err == nil
+}
+
+/// encode maps a symbol onto its code
+func (self *Alphabet) encode(c rune) (byte, error) {
+	var code byte
+	if 0 <= c && c < 128 {
+		code = self.ascii[c]
+	} else {
+		code = self.other[c]
+	}
+
+	if code == 0 {
+		return 0, InvalidBaseError(c)
+	}
+	return code, nil
+}
+
+/// encodeMasked maps a symbol onto its code, also accepting the lowercase
+/// version of any symbol and reporting it as masked.
+func (self *Alphabet) encodeMasked(c rune) (byte, bool, error) {
+	code, err := self.encode(c)
+	if err == nil {
+		return code, false, nil
+	}
+
+	if upper := unicode.ToUpper(c); upper != c {
+		if code, err := self.encode(upper); err == nil {
+			return code, true, nil
+		}
+	}
+	return 0, false, err
+}
+
+/// isNucleic returns true if the alphabet describes DNA or RNA, and so
+/// supports ambiguity matching and complementing.
+func (self *Alphabet) isNucleic() bool {
+	return self.masks != nil
+}
+
+/// compatibleWith checks whether codes from two alphabets mean the same
+/// thing, i.e. they are both nucleic acids or have the same name and
+/// symbols. Custom alphabets are compared by value, as each one read back
+/// from binary data is a fresh copy.
+func (self *Alphabet) compatibleWith(other *Alphabet) bool {
+	if self == other || (self.isNucleic() && other.isNucleic()) {
+		return true
+	}
+	return self.isNucleic() == other.isNucleic() &&
+		self.name == other.name &&
+		slices.Equal(self.symbols, other.symbols)
+}
+
+/// ambiguous returns true if the code stands for more than one base
+func (self *Alphabet) ambiguous(code byte) bool {
+	return self.masks != nil && bits.OnesCount8(self.masks[code]) > 1
+}
+
+func (self *Alphabet) codesMatch(x, y byte) bool {
+	if self.masks != nil {
+		return self.masks[x]&self.masks[y] != 0
+	}
+	return x == y
+}
+
+/// mustBeNucleic panics if the alphabet doesn't describe a nucleic acid
+func (self *Alphabet) mustBeNucleic(op string) {
+	if !self.isNucleic() {
+		panic(fmt.Sprintf("basestring: %s requires a nucleic acid alphabet, not %s",
+			op, self.name))
+	}
+}
+
+/// IncompatibleAlphabetError is returned when trying to reinterpret a string
+/// in an alphabet that doesn't share its encoding.
+type IncompatibleAlphabetError struct {
+	From *Alphabet
+	To   *Alphabet
+}
+
+func (self IncompatibleAlphabetError) Error() string {
+	return fmt.Sprintf("Can't convert from %s to %s", self.From.name, self.To.name)
+}
+
+/// sharesEncoding checks whether two alphabets encode the same number of
+/// symbols in the same width, so that one can be swapped for the other.
+func (self *Alphabet) sharesEncoding(other *Alphabet) bool {
+	return self.width == other.width &&
+		len(self.symbols) == len(other.symbols) &&
+		(self.masks == nil) == (other.masks == nil)
+}
@@ -0,0 +1,121 @@
+package basestring
+
+import (
+	"testing"
+)
+
+func Test_AlphabetsUseSmallestWidth(t *testing.T) {
+	cases := map[*Alphabet]int{
+		DNA:     4,
+		RNA:     4,
+		Protein: 5,
+	}
+	for a, expected := range cases {
+		if a.BitsPerSymbol() != expected {
+			t.Errorf("Expected %s to use %d bits, got %d", a.Name(), expected, a.BitsPerSymbol())
+		}
+	}
+
+	binary, _ := NewAlphabet("binary", "01")
+	if binary.BitsPerSymbol() != 2 {
+		t.Errorf("Expected 2 bits, got %d", binary.BitsPerSymbol())
+	}
+}
+
+func Test_InvalidAlphabetsAreRejected(t *testing.T) {
+	for _, symbols := range []string{"", "ABCA"} {
+		if _, err := NewAlphabet("bad", symbols); err == nil {
+			t.Errorf("Expected alphabet \"%s\" to be rejected", symbols)
+		}
+	}
+}
+
+func Test_ProteinStringsRoundTrip(t *testing.T) {
+	text := "MAMAPRTEINSTRING*ACDEFGHIKLMNPQRSTVWYX"
+	s, err := Protein.FromString(text)
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+	if len(s.chars) != (5*len(text)+7)/8 {
+		t.Errorf("Expected %d bytes, got %d", (5*len(text)+7)/8, len(s.chars))
+	}
+	if s.String() != text {
+		t.Errorf("Expected \"%s\", got \"%s\"", text, s.String())
+	}
+
+	for i := 0; i <= len(text); i++ {
+		for j := i; j <= len(text); j++ {
+			if v := s.Slice(i, j).String(); v != text[i:j] {
+				t.Errorf("Expected \"%s\", got \"%s\"", text[i:j], v)
+			}
+		}
+	}
+
+	if _, err := Protein.FromString("MAMAPRTEINZ"); err == nil {
+		t.Error("Expected conversion to fail")
+	}
+}
+
+func Test_CustomAlphabetsWork(t *testing.T) {
+	a, _ := NewAlphabet("greek", "αβγδεζηθικλμ")
+	s, err := a.FromString("αβγ")
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+	if s.Length() != 3 || s.At(1) != 'β' || s.String() != "αβγ" {
+		t.Errorf("Expected \"αβγ\", got \"%s\"", s.String())
+	}
+}
+
+func Test_SwappingBetweenDnaAndRna(t *testing.T) {
+	dna, _ := FromString("GATTACA")
+	rna, err := dna.WithAlphabet(RNA)
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+	if rna.String() != "GAUUACA" {
+		t.Errorf("Expected \"GAUUACA\", got \"%s\"", rna.String())
+	}
+	if &rna.chars[0] != &dna.chars[0] {
+		t.Error("Expected conversion to share storage")
+	}
+
+	back, _ := rna.ReverseComplement().WithAlphabet(DNA)
+	if back.String() != "TGTAATC" {
+		t.Errorf("Expected \"TGTAATC\", got \"%s\"", back.String())
+	}
+
+	if _, err := dna.WithAlphabet(Protein); err == nil {
+		t.Error("Expected conversion to protein to fail")
+	}
+}
+
+func Test_BuilderWorksWithWideAlphabets(t *testing.T) {
+	b := NewBuilder(Protein)
+	b.AppendString("MAMA")
+	b.Append('P')
+	if err := b.AppendString("RTEIN!"); err == nil {
+		t.Fatal("Expected append to fail")
+	}
+	b.AppendString("RTEIN")
+
+	if s := b.Build().String(); s != "MAMAPRTEIN" {
+		t.Errorf("Expected \"MAMAPRTEIN\", got \"%s\"", s)
+	}
+}
+
+func Test_ProteinsOnlyMatchThemselves(t *testing.T) {
+	s, _ := Protein.FromString("MAMAPRTEIN")
+	p, _ := Protein.FromString("PRT")
+	if s.Index(p) != 4 {
+		t.Errorf("Expected match at 4, got %d", s.Index(p))
+	}
+	if Protein.Matches('N', 'A') {
+		t.Error("Expected N not to match A in a protein")
+	}
+
+	dna, _ := FromString("NNNNNNN")
+	if dna.Index(p) != -1 {
+		t.Error("Expected protein not to match DNA")
+	}
+}
@@ -1,10 +1,29 @@
 package basestring
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
-type baseString struct {
-	chars  []byte
-	length int
+/// BaseString is a compact string of symbols from an Alphabet, bit-packed at
+/// the alphabet's symbol width. DNA and RNA use four bits per base, with the
+/// even-indexed base of each pair in the low nibble of a byte. A BaseString
+/// may be a view onto a larger string, in which case offset is the index of
+/// its first symbol within chars.
+///
+/// Lowercase (soft-masked) symbols are recorded separately as a sorted list
+/// of intervals, which may also be shared with a larger string. maskStart is
+/// the position in the mask's coordinates of the string's first symbol.
+type BaseString struct {
+	chars     []byte
+	alphabet  *Alphabet
+	offset    int
+	length    int
+	mask      []Interval
+	maskStart int
 }
 
 // ----------------------------------------------------------------------------
@@ -21,69 +40,258 @@ func (self InvalidBaseError) Error() string {
 //
 // ----------------------------------------------------------------------------
 
-func New() baseString {
-	return baseString{
-		chars:  make([]byte, 0),
-		length: 0,
+func New() BaseString {
+	return BaseString{
+		chars:    make([]byte, 0),
+		alphabet: DNA,
+		offset:   0,
+		length:   0,
 	}
 }
 
+/// toBaseChar maps a DNA base (or IUPAC ambiguity code) onto its nibble value.
+/// The four unambiguous bases take the values 1-4; the ambiguity codes fill
+/// the rest of the nibble. Zero is never a valid base.
 func toBaseChar(c rune) (byte, error) {
-	switch c {
-	case 'G':
-		return 1, nil
-	case 'A':
-		return 2, nil
-	case 'T':
-		return 3, nil
-	case 'C':
-		return 4, nil
-	}
-	return 0, InvalidBaseError(c)
+	return DNA.encode(c)
 }
 
-/// FromString creates new string of bases from an arbitrary text string.
-func FromString(s string) (baseString, error) {
-	cb := (len(s) + 1) / 2
+/// Matches checks whether two DNA bases could be the same base, treating
+/// IUPAC ambiguity codes as the set of bases they represent. For example, 'N'
+/// matches anything and 'R' matches both 'A' and 'G'. Invalid chars never
+/// match.
+func Matches(a, b rune) bool {
+	return DNA.Matches(a, b)
+}
 
-	str := baseString{
-		chars:  make([]byte, cb),
-		length: len(s),
-	}
+/// FromString creates new string of DNA bases from an arbitrary text string.
+/// Lowercase bases are accepted, and recorded as soft-masked.
+func FromString(s string) (BaseString, error) {
+	return DNA.FromString(s)
+}
+
+/// FromString creates a new string of symbols in this alphabet from an
+/// arbitrary text string. Lowercase versions of the alphabet's symbols are
+/// accepted, and recorded as soft-masked.
+func (self *Alphabet) FromString(s string) (BaseString, error) {
+	str := newBaseString(self, utf8.RuneCountInString(s))
 
 	i := 0
 	for _, c := range s {
-		if err := str.setBase(i, c); err != nil {
-			return baseString{nil, 0}, err
+		base, masked, err := self.encodeMasked(c)
+		if err != nil {
+			return BaseString{alphabet: self}, err
+		}
+		str.setCode(i, base)
+		if masked {
+			str.mask = appendToRun(str.mask, i)
 		}
 		i++
 	}
 	return str, nil
 }
 
+func newBaseString(alphabet *Alphabet, n int) BaseString {
+	return BaseString{
+		chars:    make([]byte, packedSize(alphabet, n)),
+		alphabet: alphabet,
+		offset:   0,
+		length:   n,
+	}
+}
+
+/// packedSize returns the number of bytes needed to hold n symbols
+func packedSize(alphabet *Alphabet, n int) int {
+	return (n*int(alphabet.width) + 7) / 8
+}
+
 /// Returns the length of the string in bases
-func (self *baseString) Length() int {
+func (self BaseString) Length() int {
 	return self.length
 }
 
-/// sets a base value in the string at the given index
-func (self *baseString) setBase(i int, b rune) error {
-	base, err := toBaseChar(b)
-	if err != nil {
-		return err
+/// Alphabet returns the alphabet the string is encoded in
+func (self BaseString) Alphabet() *Alphabet {
+	if self.alphabet == nil {
+		return DNA
+	}
+	return self.alphabet
+}
+
+/// WithAlphabet reinterprets the string in another alphabet without
+/// re-encoding it, e.g. to convert between DNA and RNA. The result shares
+/// storage with the original. The alphabets must share an encoding.
+func (self BaseString) WithAlphabet(alphabet *Alphabet) (BaseString, error) {
+	if !self.Alphabet().sharesEncoding(alphabet) {
+		return BaseString{}, IncompatibleAlphabetError{self.Alphabet(), alphabet}
+	}
+	self.alphabet = alphabet
+	return self, nil
+}
+
+/// At decodes the base at index i, ignoring any soft-masking. Panics if i is
+/// out of range.
+func (self BaseString) At(i int) rune {
+	if i < 0 || i >= self.length {
+		panic(fmt.Sprintf("basestring: index %d out of range [0:%d]", i, self.length))
+	}
+	return self.Alphabet().symbols[self.base(i)]
+}
+
+/// String decodes the entire string of bases back into text, with
+/// soft-masked bases in lowercase.
+func (self BaseString) String() string {
+	symbols := self.Alphabet().symbols
+	var result strings.Builder
+	result.Grow(self.length)
+
+	i := 0
+	for interval := range self.MaskedIntervals() {
+		for ; i < interval.Start; i++ {
+			result.WriteRune(symbols[self.base(i)])
+		}
+		for ; i < interval.End; i++ {
+			result.WriteRune(unicode.ToLower(symbols[self.base(i)]))
+		}
+	}
+	for ; i < self.length; i++ {
+		result.WriteRune(symbols[self.base(i)])
+	}
+	return result.String()
+}
+
+/// Slice returns a view of the bases in [i, j). The view shares storage with
+/// the original string, so no bases are copied. Panics if the bounds are
+/// invalid.
+func (self BaseString) Slice(i, j int) BaseString {
+	if i < 0 || j < i || j > self.length {
+		panic(fmt.Sprintf("basestring: slice bounds [%d:%d] out of range [0:%d]",
+			i, j, self.length))
+	}
+
+	alphabet := self.Alphabet()
+	start := self.offset + i
+	end := (self.offset + j) * int(alphabet.width)
+
+	// leading bytes can only be dropped if they hold a whole number of
+	// symbols, which is only guaranteed for nibble-packed alphabets
+	skip := 0
+	if alphabet.width == 4 {
+		skip = start / 2
+	}
+
+	return BaseString{
+		chars:     self.chars[skip : (end+7)/8],
+		alphabet:  alphabet,
+		offset:    start - 2*skip,
+		length:    j - i,
+		mask:      self.mask,
+		maskStart: self.maskStart + i,
+	}
+}
+
+/// All returns an iterator over the index and value of each base in the
+/// string, decoded straight from the packed representation.
+func (self BaseString) All() iter.Seq2[int, rune] {
+	symbols := self.Alphabet().symbols
+	return func(yield func(int, rune) bool) {
+		for i := 0; i < self.length; i++ {
+			if !yield(i, symbols[self.base(i)]) {
+				return
+			}
+		}
+	}
+}
+
+/// IsAmbiguous returns true if the base at index i is an IUPAC ambiguity code
+/// rather than a single, known base. Always false for non-nucleic alphabets.
+func (self BaseString) IsAmbiguous(i int) bool {
+	if i < 0 || i >= self.length {
+		panic(fmt.Sprintf("basestring: index %d out of range [0:%d]", i, self.length))
 	}
+	return self.Alphabet().ambiguous(self.base(i))
+}
 
-	byteOffset := i / 2
-	nibbleOffset := uint(i % 2)
-	pair := self.chars[byteOffset]
+/// MatchAt checks whether pattern matches the string starting at index i,
+/// with ambiguity codes in either string matching any of the bases they
+/// stand for. Strings in incompatible alphabets never match.
+func (self BaseString) MatchAt(i int, pattern BaseString) bool {
+	alphabet := self.Alphabet()
+	if !alphabet.compatibleWith(pattern.Alphabet()) {
+		return false
+	}
 
-	// surely there's a way we can do this without branching
-	if nibbleOffset == 0 {
-		pair = (pair & 0xF0) | byte(base)
-	} else {
-		pair = (pair & 0x0F) | byte(base<<4)
+	if i < 0 || i+pattern.length > self.length {
+		return false
+	}
+	for j := 0; j < pattern.length; j++ {
+		if !alphabet.codesMatch(self.base(i+j), pattern.base(j)) {
+			return false
+		}
 	}
+	return true
+}
 
-	self.chars[byteOffset] = pair
-	return nil
+/// Index returns the index of the first place in the string that matches
+/// pattern (as per MatchAt), or -1 if there is no match.
+func (self BaseString) Index(pattern BaseString) int {
+	for i := 0; i+pattern.length <= self.length; i++ {
+		if self.MatchAt(i, pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+/// base fetches the raw encoded value of the base at index i
+func (self BaseString) base(i int) byte {
+	n := self.offset + i
+	if self.alphabet == nil || self.alphabet.width == 4 {
+		pair := self.chars[n/2]
+		if n%2 == 0 {
+			return pair & 0x0F
+		}
+		return pair >> 4
+	}
+
+	width := self.alphabet.width
+	bit := uint(n) * width
+	v := uint16(self.chars[bit/8])
+	if bit%8+width > 8 {
+		v |= uint16(self.chars[bit/8+1]) << 8
+	}
+	return byte(v>>(bit%8)) & byte(1<<width-1)
+}
+
+/// setCode writes a raw encoded value into the string at the given index
+func (self *BaseString) setCode(i int, base byte) {
+	n := self.offset + i
+	if self.alphabet == nil || self.alphabet.width == 4 {
+		byteOffset := n / 2
+		nibbleOffset := uint(n % 2)
+		pair := self.chars[byteOffset]
+
+		// surely there's a way we can do this without branching
+		if nibbleOffset == 0 {
+			pair = (pair & 0xF0) | byte(base)
+		} else {
+			pair = (pair & 0x0F) | byte(base<<4)
+		}
+
+		self.chars[byteOffset] = pair
+		return
+	}
+
+	width := self.alphabet.width
+	bit := uint(n) * width
+	mask := uint16(1<<width-1) << (bit % 8)
+	v := uint16(self.chars[bit/8])
+	if bit%8+width > 8 {
+		v |= uint16(self.chars[bit/8+1]) << 8
+	}
+	v = (v &^ mask) | (uint16(base) << (bit % 8))
+	self.chars[bit/8] = byte(v)
+	if bit%8+width > 8 {
+		self.chars[bit/8+1] = byte(v >> 8)
+	}
 }
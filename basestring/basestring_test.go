@@ -26,3 +26,139 @@ func Test_CreatingBaseStringWithInvalidCharsFails(t *testing.T) {
 		t.Fatal("Expected conversion to fail")
 	}
 }
+
+func Test_AtDecodesEachBase(t *testing.T) {
+	text := "GATTACA"
+	s, _ := FromString(text)
+	for i, c := range text {
+		if s.At(i) != c {
+			t.Errorf("Expected %c at %d, got %c", c, i, s.At(i))
+		}
+	}
+}
+
+func Test_StringRoundTrips(t *testing.T) {
+	for _, text := range []string{"", "G", "GATTACA", "CATGCATG"} {
+		s, err := FromString(text)
+		if err != nil {
+			t.Fatalf("Conversion failed: %s", err.Error())
+		}
+		if s.String() != text {
+			t.Errorf("Expected \"%s\", got \"%s\"", text, s.String())
+		}
+	}
+}
+
+func Test_SliceSharesStorage(t *testing.T) {
+	s, _ := FromString("GATTACA")
+	v := s.Slice(1, 6)
+	if v.String() != "ATTAC" {
+		t.Errorf("Expected \"ATTAC\", got \"%s\"", v.String())
+	}
+	if &v.chars[0] != &s.chars[0] {
+		t.Error("Expected slice to share storage with the original")
+	}
+}
+
+func Test_SliceOfSliceWorks(t *testing.T) {
+	text := "GATTACAGATTACA"
+	s, _ := FromString(text)
+	for i := 0; i <= s.Length(); i++ {
+		for j := i; j <= s.Length(); j++ {
+			v := s.Slice(1, s.Length()).Slice(0, s.Length()-1)
+			if i < v.Length() && j <= v.Length() {
+				if v.Slice(i, j).String() != text[1+i:1+j] {
+					t.Errorf("Expected \"%s\", got \"%s\"",
+						text[1+i:1+j], v.Slice(i, j).String())
+				}
+			}
+		}
+	}
+}
+
+func Test_SliceOutOfRangePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic")
+		}
+	}()
+	s, _ := FromString("GATTACA")
+	s.Slice(2, 8)
+}
+
+func Test_IterationVisitsEveryBaseInOrder(t *testing.T) {
+	s, _ := FromString("GATTACA")
+	result := []rune{}
+	for i, c := range s.Slice(1, 5).All() {
+		if i != len(result) {
+			t.Errorf("Expected index %d, got %d", len(result), i)
+		}
+		result = append(result, c)
+	}
+	if string(result) != "ATTA" {
+		t.Errorf("Expected \"ATTA\", got \"%s\"", string(result))
+	}
+}
+
+func Test_AmbiguityCodesRoundTrip(t *testing.T) {
+	text := "GATTACANNNNRYKMSWBDHV"
+	s, err := FromString(text)
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+	if s.String() != text {
+		t.Errorf("Expected \"%s\", got \"%s\"", text, s.String())
+	}
+	for i := range text {
+		if s.IsAmbiguous(i) != (i >= 7) {
+			t.Errorf("Wrong ambiguity flag for %c at %d", text[i], i)
+		}
+	}
+}
+
+func Test_AmbiguousBasesMatchAsSets(t *testing.T) {
+	cases := []struct {
+		a, b     rune
+		expected bool
+	}{
+		{'A', 'A', true},
+		{'A', 'C', false},
+		{'N', 'G', true},
+		{'N', 'N', true},
+		{'R', 'A', true},
+		{'R', 'G', true},
+		{'R', 'C', false},
+		{'R', 'Y', false},
+		{'B', 'A', false},
+		{'B', 'S', true},
+		{'A', 'X', false},
+	}
+
+	for _, c := range cases {
+		if Matches(c.a, c.b) != c.expected {
+			t.Errorf("Expected Matches(%c, %c) == %v", c.a, c.b, c.expected)
+		}
+		if Matches(c.b, c.a) != c.expected {
+			t.Errorf("Expected Matches(%c, %c) == %v", c.b, c.a, c.expected)
+		}
+	}
+}
+
+func Test_IndexFindsAmbiguousMatches(t *testing.T) {
+	s, _ := FromString("GATTACANNGAC")
+	cases := map[string]int{
+		"TTA":  2,
+		"TWA":  2,
+		"ACAG": 4,
+		"ACCG": 6,
+		"GACT": -1,
+		"NNNN": 0,
+	}
+
+	for text, expected := range cases {
+		pattern, _ := FromString(text)
+		if i := s.Index(pattern); i != expected {
+			t.Errorf("Expected Index(%s) == %d, got %d", text, expected, i)
+		}
+	}
+}
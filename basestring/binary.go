@@ -0,0 +1,327 @@
+package basestring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+/// The binary form of a BaseString is laid out as follows, with all integers
+/// little-endian:
+///
+///    magic      4 bytes   "BSTR"
+///    version    1 byte
+///    alphabet   1 byte    see alphabetIds; 0 means custom, in which case
+///                         it is followed by the alphabet name (1 byte
+///                         length + text) and symbols (2 byte length + UTF-8
+///                         text)
+///    length     8 bytes   in symbols
+///    data       packed symbols, starting at the first bit
+///    masked     8 bytes   number of soft-masked intervals (version 2+),
+///                         followed by the start & end of each (8 bytes each)
+///    checksum   4 bytes   CRC-32 (IEEE) of everything before it
+///
+/// Version 1 has no soft-masking section.
+const (
+	binaryMagic   = "BSTR"
+	binaryVersion = 2
+)
+
+/// alphabetIds assigns the built-in alphabets a fixed id in the binary format
+var alphabetIds = []*Alphabet{nil, DNA, RNA, Protein}
+
+/// CorruptDataError describes a malformed serialized BaseString
+type CorruptDataError string
+
+func (self CorruptDataError) Error() string {
+	return fmt.Sprintf("Corrupt base string data: %s", string(self))
+}
+
+/// UnsupportedVersionError is returned when reading a serialized BaseString
+/// written by a newer version of the format.
+type UnsupportedVersionError uint8
+
+func (self UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("Unsupported base string format version: %d", uint8(self))
+}
+
+/// ChecksumError is returned when a serialized BaseString fails its checksum
+type ChecksumError struct {
+	Expected uint32
+	Actual   uint32
+}
+
+func (self ChecksumError) Error() string {
+	return fmt.Sprintf("Base string checksum mismatch: expected %08x, got %08x",
+		self.Expected, self.Actual)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// MarshalBinary implements encoding.BinaryMarshaler
+func (self BaseString) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := self.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (self *BaseString) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := self.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return CorruptDataError("trailing data")
+	}
+	return nil
+}
+
+/// WriteTo implements io.WriterTo, writing the string out in binary form.
+func (self BaseString) WriteTo(w io.Writer) (int64, error) {
+	alphabet := self.Alphabet()
+	hash := crc32.NewIEEE()
+	out := countingWriter{w: io.MultiWriter(w, hash)}
+
+	var header bytes.Buffer
+	header.WriteString(binaryMagic)
+	header.WriteByte(binaryVersion)
+
+	id := alphabetId(alphabet)
+	header.WriteByte(byte(id))
+	if id == 0 {
+		symbols := alphabet.Symbols()
+		if len(alphabet.name) > math.MaxUint8 {
+			return 0, fmt.Errorf("Alphabet name too long: %s", alphabet.name)
+		}
+		header.WriteByte(byte(len(alphabet.name)))
+		header.WriteString(alphabet.name)
+		binary.Write(&header, binary.LittleEndian, uint16(len(symbols)))
+		header.WriteString(symbols)
+	}
+	binary.Write(&header, binary.LittleEndian, uint64(self.length))
+
+	out.Write(header.Bytes())
+	out.Write(self.packed())
+
+	mask := self.maskedIntervals()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(len(mask)))
+	out.Write(buf[:])
+	for _, interval := range mask {
+		binary.LittleEndian.PutUint64(buf[:], uint64(interval.Start))
+		out.Write(buf[:])
+		binary.LittleEndian.PutUint64(buf[:], uint64(interval.End))
+		out.Write(buf[:])
+	}
+
+	if out.err != nil {
+		return out.n, out.err
+	}
+
+	var checksum [4]byte
+	binary.LittleEndian.PutUint32(checksum[:], hash.Sum32())
+	n, err := w.Write(checksum[:])
+	return out.n + int64(n), err
+}
+
+/// ReadFrom implements io.ReaderFrom, replacing the string with one read in
+/// binary form. Exactly one string is consumed from r.
+func (self *BaseString) ReadFrom(r io.Reader) (int64, error) {
+	hash := crc32.NewIEEE()
+	in := &countingReader{r: io.TeeReader(r, hash)}
+
+	var header [6]byte
+	if _, err := io.ReadFull(in, header[:]); err != nil {
+		return in.n, unexpectedEOF(err)
+	}
+	if string(header[:4]) != binaryMagic {
+		return in.n, CorruptDataError("bad magic")
+	}
+	version := header[4]
+	if version < 1 || version > binaryVersion {
+		return in.n, UnsupportedVersionError(version)
+	}
+
+	alphabet, err := readAlphabet(in, header[5])
+	if err != nil {
+		return in.n, err
+	}
+
+	var length uint64
+	if err := binary.Read(in, binary.LittleEndian, &length); err != nil {
+		return in.n, unexpectedEOF(err)
+	}
+	if length > math.MaxInt64/8 {
+		return in.n, CorruptDataError(fmt.Sprintf("implausible length %d", length))
+	}
+
+	// don't trust the length enough to allocate it all up front; a corrupt
+	// header shouldn't be able to exhaust memory before we hit EOF.
+	size := int64(packedSize(alphabet, int(length)))
+	var data bytes.Buffer
+	data.Grow(int(min(size, 1<<20)))
+	if _, err := io.CopyN(&data, in, size); err != nil {
+		return in.n, unexpectedEOF(err)
+	}
+
+	var mask []Interval
+	if version >= 2 {
+		if mask, err = readMask(in, int(length)); err != nil {
+			return in.n, err
+		}
+	}
+
+	expected := hash.Sum32()
+	var checksum [4]byte
+	if _, err := io.ReadFull(r, checksum[:]); err != nil {
+		return in.n, unexpectedEOF(err)
+	}
+	n := in.n + 4
+	if actual := binary.LittleEndian.Uint32(checksum[:]); actual != expected {
+		return n, ChecksumError{Expected: expected, Actual: actual}
+	}
+
+	str := BaseString{
+		chars:    data.Bytes(),
+		alphabet: alphabet,
+		offset:   0,
+		length:   int(length),
+		mask:     mask,
+	}
+	for i := 0; i < str.length; i++ {
+		if code := str.base(i); code == 0 || int(code) >= len(alphabet.symbols) {
+			return n, CorruptDataError(fmt.Sprintf("invalid code %d at %d", code, i))
+		}
+	}
+
+	*self = str
+	return n, nil
+}
+
+/// packed returns the string's symbols packed from the first bit of the
+/// first byte, with any trailing bits zeroed. Only views that don't already
+/// look like that are copied.
+func (self BaseString) packed() []byte {
+	alphabet := self.Alphabet()
+	size := packedSize(alphabet, self.length)
+	if self.offset == 0 && (self.length*int(alphabet.width))%8 == 0 {
+		return self.chars[:size]
+	}
+
+	str := newBaseString(alphabet, self.length)
+	for i := 0; i < self.length; i++ {
+		str.setCode(i, self.base(i))
+	}
+	return str.chars
+}
+
+func alphabetId(alphabet *Alphabet) int {
+	for id, a := range alphabetIds {
+		if a == alphabet {
+			return id
+		}
+	}
+	return 0
+}
+
+/// readAlphabet reads the alphabet description from the header
+func readAlphabet(r io.Reader, id byte) (*Alphabet, error) {
+	if id != 0 {
+		if int(id) >= len(alphabetIds) {
+			return nil, CorruptDataError(fmt.Sprintf("unknown alphabet %d", id))
+		}
+		return alphabetIds[id], nil
+	}
+
+	var nameLength [1]byte
+	if _, err := io.ReadFull(r, nameLength[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	name := make([]byte, nameLength[0])
+	if _, err := io.ReadFull(r, name); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	var symbolsLength uint16
+	if err := binary.Read(r, binary.LittleEndian, &symbolsLength); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	symbols := make([]byte, symbolsLength)
+	if _, err := io.ReadFull(r, symbols); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	alphabet, err := NewAlphabet(string(name), string(symbols))
+	if err != nil {
+		return nil, CorruptDataError(err.Error())
+	}
+	return alphabet, nil
+}
+
+/// readMask reads and validates the soft-masked intervals of a string of the
+/// given length.
+func readMask(r io.Reader, length int) ([]Interval, error) {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if count > uint64(length) {
+		return nil, CorruptDataError(fmt.Sprintf("too many masked intervals (%d)", count))
+	}
+
+	mask := []Interval{}
+	prev := 0
+	for i := uint64(0); i < count; i++ {
+		var bounds [2]uint64
+		if err := binary.Read(r, binary.LittleEndian, &bounds); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		start, end := bounds[0], bounds[1]
+		if start < uint64(prev) || end <= start || end > uint64(length) {
+			return nil, CorruptDataError(
+				fmt.Sprintf("bad masked interval [%d:%d]", start, end))
+		}
+		mask = append(mask, Interval{int(start), int(end)})
+		prev = int(end)
+	}
+	return mask, nil
+}
+
+/// countingWriter tracks the number of bytes written, and remembers the
+/// first error so that a sequence of writes can be checked once at the end.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (self *countingWriter) Write(buf []byte) (int, error) {
+	if self.err != nil {
+		return 0, self.err
+	}
+	n, err := self.w.Write(buf)
+	self.n += int64(n)
+	self.err = err
+	return n, err
+}
+
+/// countingReader tracks the number of bytes read
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (self *countingReader) Read(buf []byte) (int, error) {
+	n, err := self.r.Read(buf)
+	self.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,126 @@
+package basestring
+
+import (
+	"bytes"
+	"encoding"
+	"io"
+	"testing"
+)
+
+var _ encoding.BinaryMarshaler = BaseString{}
+var _ encoding.BinaryUnmarshaler = &BaseString{}
+var _ io.WriterTo = BaseString{}
+var _ io.ReaderFrom = &BaseString{}
+
+func Test_BinaryRoundTrips(t *testing.T) {
+	dna, _ := FromString("GATTACANNRYGATTACA")
+	rna, _ := RNA.FromString("GAUUACA")
+	protein, _ := Protein.FromString("MAMAPRTEIN*")
+	greek, _ := NewAlphabet("greek", "αβγ")
+	custom, _ := greek.FromString("αββγα")
+
+	for _, s := range []BaseString{New(), dna, dna.Slice(3, 12), rna, protein, protein.Slice(1, 8), custom} {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Marshal failed: %s", err.Error())
+		}
+
+		var result BaseString
+		if err := result.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Unmarshal failed: %s", err.Error())
+		}
+
+		if result.String() != s.String() {
+			t.Errorf("Expected \"%s\", got \"%s\"", s.String(), result.String())
+		}
+		if result.Alphabet().Symbols() != s.Alphabet().Symbols() {
+			t.Errorf("Expected alphabet %s, got %s", s.Alphabet(), result.Alphabet())
+		}
+	}
+}
+
+func Test_ReadFromConsumesOneString(t *testing.T) {
+	a, _ := FromString("GATTACA")
+	b, _ := Protein.FromString("MAMAPRTEIN")
+
+	var buf bytes.Buffer
+	na, _ := a.WriteTo(&buf)
+	nb, _ := b.WriteTo(&buf)
+	if int64(buf.Len()) != na+nb {
+		t.Errorf("Expected %d bytes written, got %d", na+nb, buf.Len())
+	}
+
+	var s BaseString
+	if n, err := s.ReadFrom(&buf); err != nil || n != na || s.String() != "GATTACA" {
+		t.Errorf("Expected GATTACA (%d bytes), got %s (%d bytes, err: %v)", na, s.String(), n, err)
+	}
+	if n, err := s.ReadFrom(&buf); err != nil || n != nb || s.String() != "MAMAPRTEIN" {
+		t.Errorf("Expected MAMAPRTEIN (%d bytes), got %s (%d bytes, err: %v)", nb, s.String(), n, err)
+	}
+}
+
+func Test_CorruptBinaryDataIsRejected(t *testing.T) {
+	s, _ := FromString("GATTACA")
+	data, _ := s.MarshalBinary()
+
+	corrupt := func(i int, b byte) []byte {
+		result := append([]byte(nil), data...)
+		result[i] = b
+		return result
+	}
+
+	var result BaseString
+	if _, ok := result.UnmarshalBinary(corrupt(0, 'X')).(CorruptDataError); !ok {
+		t.Error("Expected bad magic to be rejected")
+	}
+
+	if err := result.UnmarshalBinary(corrupt(4, 99)); err != UnsupportedVersionError(99) {
+		t.Errorf("Expected UnsupportedVersionError, got %v", err)
+	}
+
+	if _, ok := result.UnmarshalBinary(corrupt(5, 42)).(CorruptDataError); !ok {
+		t.Error("Expected unknown alphabet to be rejected")
+	}
+
+	if _, ok := result.UnmarshalBinary(corrupt(15, 0x44)).(ChecksumError); !ok {
+		t.Error("Expected checksum mismatch")
+	}
+
+	if err := result.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Error("Expected trailing data to be rejected")
+	}
+}
+
+func Test_TruncatedBinaryDataIsRejected(t *testing.T) {
+	s, _ := FromString("GATTACA")
+	data, _ := s.MarshalBinary()
+
+	for n := 0; n < len(data); n++ {
+		var result BaseString
+		if err := result.UnmarshalBinary(data[:n]); err != io.ErrUnexpectedEOF {
+			t.Errorf("Expected io.ErrUnexpectedEOF for %d bytes, got %v", n, err)
+		}
+	}
+}
+
+func Test_UnmarshalledCustomAlphabetsMatchTheOriginal(t *testing.T) {
+	greek, _ := NewAlphabet("greek", "αβγ")
+	s, _ := greek.FromString("αββγα")
+	data, _ := s.MarshalBinary()
+
+	var result BaseString
+	if err := result.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err.Error())
+	}
+
+	pattern, _ := greek.FromString("βγ")
+	if result.Index(pattern) != 2 {
+		t.Errorf("Expected match at 2, got %d", result.Index(pattern))
+	}
+
+	other, _ := NewAlphabet("greek", "αγβ")
+	reordered, _ := other.FromString("βγ")
+	if result.Index(reordered) != -1 {
+		t.Error("Expected alphabets with different symbols not to match")
+	}
+}
@@ -0,0 +1,145 @@
+package basestring
+
+/// Builder incrementally packs bases into a BaseString, so that a sequence can
+/// be built up from chunks (e.g. the lines of a FASTA record) without ever
+/// holding the whole thing as text. Lowercase bases are recorded as
+/// soft-masked. The zero value is ready to use, and builds DNA strings.
+type Builder struct {
+	chars    []byte
+	alphabet *Alphabet
+	length   int
+	mask     []Interval
+
+	// shared is set when chars and mask are referenced by a string handed
+	// out by Build(), in which case the builder must copy before writing
+	// again.
+	shared bool
+}
+
+/// NewBuilder creates a builder for strings in the given alphabet.
+func NewBuilder(alphabet *Alphabet) *Builder {
+	return &Builder{alphabet: alphabet}
+}
+
+/// Returns the number of bases appended so far
+func (self *Builder) Length() int {
+	return self.length
+}
+
+/// Grow ensures there is room for at least another n bases without
+/// reallocating.
+func (self *Builder) Grow(n int) {
+	need := packedSize(self.Alphabet(), self.length+n)
+	if self.shared || cap(self.chars) < need {
+		chars := make([]byte, len(self.chars), max(need, 2*cap(self.chars)))
+		copy(chars, self.chars)
+		self.chars = chars
+	}
+	if self.shared {
+		self.mask = append([]Interval(nil), self.mask...)
+		self.shared = false
+	}
+}
+
+/// Append adds a single base to the end of the string.
+func (self *Builder) Append(c rune) error {
+	base, masked, err := self.Alphabet().encodeMasked(c)
+	if err != nil {
+		return err
+	}
+	self.Grow(1)
+	self.appendCode(base, masked)
+	return nil
+}
+
+/// AppendString adds a run of bases to the end of the string. If s holds an
+/// invalid base, an error is returned and the builder is left as it was.
+func (self *Builder) AppendString(s string) error {
+	alphabet := self.Alphabet()
+	start := self.length
+	self.Grow(len(s))
+	for _, c := range s {
+		base, masked, err := alphabet.encodeMasked(c)
+		if err != nil {
+			self.truncate(start)
+			return err
+		}
+		self.appendCode(base, masked)
+	}
+	return nil
+}
+
+/// Build returns the bases appended so far as a BaseString. The result is
+/// immutable; appending more bases to the builder afterwards won't affect
+/// it.
+func (self *Builder) Build() BaseString {
+	n := len(self.chars)
+	self.shared = true
+	return BaseString{
+		chars:    self.chars[:n:n],
+		alphabet: self.Alphabet(),
+		offset:   0,
+		length:   self.length,
+		mask:     self.mask[:len(self.mask):len(self.mask)],
+	}
+}
+
+/// Alphabet returns the alphabet the builder encodes bases in
+func (self *Builder) Alphabet() *Alphabet {
+	if self.alphabet == nil {
+		return DNA
+	}
+	return self.alphabet
+}
+
+/// Reset empties the builder.
+func (self *Builder) Reset() {
+	*self = Builder{alphabet: self.alphabet}
+}
+
+/// appendCode adds an encoded value to the end of the string, starting a new
+/// byte or filling in the top of the last one as required. The caller is
+/// responsible for making sure there's room.
+func (self *Builder) appendCode(base byte, masked bool) {
+	if masked {
+		self.mask = appendToRun(self.mask, self.length)
+	}
+
+	width := self.Alphabet().width
+	if width == 4 {
+		if self.length%2 == 0 {
+			self.chars = append(self.chars, base)
+		} else {
+			self.chars[len(self.chars)-1] |= base << 4
+		}
+		self.length++
+		return
+	}
+
+	bit := uint(self.length) * width
+	for uint(len(self.chars))*8 < bit+width {
+		self.chars = append(self.chars, 0)
+	}
+	self.chars[bit/8] |= base << (bit % 8)
+	if bit%8+width > 8 {
+		self.chars[bit/8+1] |= base >> (8 - bit%8)
+	}
+	self.length++
+}
+
+/// truncate chops the string back to n bases
+func (self *Builder) truncate(n int) {
+	width := self.Alphabet().width
+	self.chars = self.chars[:packedSize(self.Alphabet(), n)]
+	if r := (uint(n) * width) % 8; r != 0 {
+		self.chars[len(self.chars)-1] &= byte(1<<r - 1)
+	}
+
+	for len(self.mask) > 0 && self.mask[len(self.mask)-1].Start >= n {
+		self.mask = self.mask[:len(self.mask)-1]
+	}
+	if k := len(self.mask); k > 0 && self.mask[k-1].End > n {
+		self.mask[k-1].End = n
+	}
+	self.length = n
+}
@@ -0,0 +1,71 @@
+package basestring
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_BuilderPacksLikeFromString(t *testing.T) {
+	var b Builder
+	for _, chunk := range []string{"GAT", "TA", "", "C", "A"} {
+		if err := b.AppendString(chunk); err != nil {
+			t.Fatalf("Append failed: %s", err.Error())
+		}
+	}
+
+	s := b.Build()
+	e, _ := FromString("GATTACA")
+	if s.Length() != 7 {
+		t.Errorf("Expected length == 7, got %d", s.Length())
+	}
+	if bytes.Compare(s.chars, e.chars) != 0 {
+		t.Errorf("expected %#v, got %#v", e.chars, s.chars)
+	}
+}
+
+func Test_BuilderAppendsSingleBases(t *testing.T) {
+	var b Builder
+	for _, c := range "GATTACA" {
+		if err := b.Append(c); err != nil {
+			t.Fatalf("Append failed: %s", err.Error())
+		}
+	}
+	if s := b.Build().String(); s != "GATTACA" {
+		t.Errorf("Expected \"GATTACA\", got \"%s\"", s)
+	}
+}
+
+func Test_BuilderRejectsInvalidBasesAtomically(t *testing.T) {
+	var b Builder
+	b.AppendString("GAT")
+	if err := b.AppendString("TANARF"); err == nil {
+		t.Fatal("Expected append to fail")
+	}
+	if err := b.Append('x'); err == nil {
+		t.Fatal("Expected append to fail")
+	}
+	b.AppendString("TACA")
+
+	if s := b.Build().String(); s != "GATTACA" {
+		t.Errorf("Expected \"GATTACA\", got \"%s\"", s)
+	}
+}
+
+func Test_BuiltStringsAreImmutable(t *testing.T) {
+	var b Builder
+	b.Grow(100)
+	b.AppendString("GAT")
+	s := b.Build()
+	before := append([]byte(nil), s.chars...)
+
+	b.AppendString("TACA")
+	if s.String() != "GAT" {
+		t.Errorf("Expected \"GAT\", got \"%s\"", s.String())
+	}
+	if bytes.Compare(s.chars, before) != 0 {
+		t.Errorf("Expected built string storage to be unchanged")
+	}
+	if b.Build().String() != "GATTACA" {
+		t.Errorf("Expected \"GATTACA\", got \"%s\"", b.Build().String())
+	}
+}
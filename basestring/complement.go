@@ -0,0 +1,92 @@
+package basestring
+
+/// complementCodes maps each nibble value onto the nibble value of its
+/// complementary base. Ambiguity codes map onto the code for the complement of
+/// the set they represent, e.g. R (A or G) becomes Y (C or T).
+var complementCodes = [16]byte{
+	0,  // invalid
+	4,  // G -> C
+	3,  // A -> T
+	2,  // T -> A
+	1,  // C -> G
+	5,  // N -> N
+	7,  // R -> Y
+	6,  // Y -> R
+	9,  // K -> M
+	8,  // M -> K
+	10, // S -> S
+	11, // W -> W
+	15, // B -> V
+	14, // D -> H
+	13, // H -> D
+	12, // V -> B
+}
+
+/// complementBytes complements both nibbles of a byte in place, while
+/// reverseComplementBytes also swaps them over.
+var complementBytes, reverseComplementBytes [256]byte
+
+func init() {
+	for i := 0; i < 256; i++ {
+		lo := complementCodes[i&0x0F]
+		hi := complementCodes[i>>4]
+		complementBytes[i] = lo | (hi << 4)
+		reverseComplementBytes[i] = hi | (lo << 4)
+	}
+}
+
+/// packedBytes returns the bytes holding the string's bases, along with the
+/// nibble offset of the first base within them. Only meaningful for nibble
+/// packed alphabets.
+func (self BaseString) packedBytes() ([]byte, int) {
+	start := self.offset
+	end := self.offset + self.length
+	return self.chars[start/2 : (end+1)/2], start % 2
+}
+
+/// Complement returns a new string holding the complement of each base,
+/// computed a byte at a time straight from the packed representation.
+/// Soft-masking is preserved. Panics
+/// if the string isn't DNA or RNA.
+func (self BaseString) Complement() BaseString {
+	self.Alphabet().mustBeNucleic("Complement")
+	src, offset := self.packedBytes()
+	dst := make([]byte, len(src))
+	for i, b := range src {
+		dst[i] = complementBytes[b]
+	}
+
+	return BaseString{
+		chars:     dst,
+		alphabet:  self.Alphabet(),
+		offset:    offset,
+		length:    self.length,
+		mask:      self.mask,
+		maskStart: self.maskStart,
+	}
+}
+
+/// ReverseComplement returns a new string holding the reverse complement of
+/// the string, computed a byte at a time straight from the packed
+/// representation. Reversing the bytes and swapping their nibbles leaves an
+/// odd-length string starting on the high nibble, so rather than shifting
+/// every base along, the result simply starts at an offset. Soft-masked
+/// regions are reversed along with the bases. Panics if the string isn't DNA
+/// or RNA.
+func (self BaseString) ReverseComplement() BaseString {
+	self.Alphabet().mustBeNucleic("ReverseComplement")
+	src, offset := self.packedBytes()
+	n := len(src)
+	dst := make([]byte, n)
+	for i, b := range src {
+		dst[n-1-i] = reverseComplementBytes[b]
+	}
+
+	return BaseString{
+		chars:    dst,
+		alphabet: self.Alphabet(),
+		offset:   2*n - offset - self.length,
+		length:   self.length,
+		mask:     reverseIntervals(self.maskedIntervals(), self.length),
+	}
+}
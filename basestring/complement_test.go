@@ -0,0 +1,71 @@
+package basestring
+
+import (
+	"testing"
+)
+
+func Test_ComplementWorks(t *testing.T) {
+	cases := map[string]string{
+		"":                 "",
+		"G":                "C",
+		"GATTACA":          "CTAATGT",
+		"NRYKMSWBDHV":      "NYRMKSWVHDB",
+		"GATTACAGATTACAGC": "CTAATGTCTAATGTCG",
+	}
+
+	for text, expected := range cases {
+		s, _ := FromString(text)
+		if c := s.Complement().String(); c != expected {
+			t.Errorf("Expected complement of %s to be %s, got %s", text, expected, c)
+		}
+	}
+}
+
+func Test_ReverseComplementWorks(t *testing.T) {
+	cases := map[string]string{
+		"":            "",
+		"G":           "C",
+		"GA":          "TC",
+		"GATTACA":     "TGTAATC",
+		"GATTACAG":    "CTGTAATC",
+		"NRYKMSWBDHV": "BDHVWSKMRYN",
+	}
+
+	for text, expected := range cases {
+		s, _ := FromString(text)
+		if rc := s.ReverseComplement().String(); rc != expected {
+			t.Errorf("Expected reverse complement of %s to be %s, got %s",
+				text, expected, rc)
+		}
+	}
+}
+
+func Test_ReverseComplementOfSlicesWorks(t *testing.T) {
+	text := "GATTACAGATTACAGC"
+	s, _ := FromString(text)
+	for i := 0; i <= len(text); i++ {
+		for j := i; j <= len(text); j++ {
+			v := s.Slice(i, j)
+			rc := v.ReverseComplement()
+			if rc.Length() != j-i {
+				t.Fatalf("Expected length %d, got %d", j-i, rc.Length())
+			}
+			if rc.ReverseComplement().String() != text[i:j] {
+				t.Errorf("Expected double reverse complement of %s to be itself, got %s",
+					text[i:j], rc.ReverseComplement().String())
+			}
+			if v.Complement().Complement().String() != text[i:j] {
+				t.Errorf("Expected double complement of %s to be itself", text[i:j])
+			}
+		}
+	}
+}
+
+func Test_ReverseComplementDoesNotModifyOriginal(t *testing.T) {
+	s, _ := FromString("GATTACA")
+	s.ReverseComplement()
+	s.Complement()
+	if s.String() != "GATTACA" {
+		t.Errorf("Expected original to be unchanged, got %s", s.String())
+	}
+}
@@ -0,0 +1,167 @@
+package basestring
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+/// lowNibbleBits has the lowest bit of every nibble in a word set
+const lowNibbleBits = 0x1111111111111111
+
+/// LengthMismatchError is returned when comparing strings of different
+/// lengths.
+type LengthMismatchError struct {
+	A int
+	B int
+}
+
+func (self LengthMismatchError) Error() string {
+	return fmt.Sprintf("String length mismatch, a: %d, b: %d", self.A, self.B)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Count returns the number of times a symbol appears in the string. Only
+/// exact matches are counted, so counting 'A' doesn't include any 'N's.
+/// Nibble-packed strings are scanned sixteen bases at a time.
+func (self BaseString) Count(base rune) int {
+	alphabet := self.Alphabet()
+	code, err := alphabet.encode(base)
+	if err != nil {
+		return 0
+	}
+
+	if alphabet.width != 4 {
+		count := 0
+		for i := 0; i < self.length; i++ {
+			if self.base(i) == code {
+				count++
+			}
+		}
+		return count
+	}
+
+	src, offset := self.packedBytes()
+	pattern := uint64(code) * lowNibbleBits
+	count := 0
+	for k := 0; k < (len(src)+7)/8; k++ {
+		same := zeroNibbles(loadWord(src, k) ^ pattern)
+		count += bits.OnesCount64(same & validNibbles(k, offset, self.length))
+	}
+	return count
+}
+
+/// Composition returns the number of times each symbol in the alphabet
+/// appears in the string. Nibble-packed strings are tallied a byte (i.e. two
+/// bases) at a time.
+func (self BaseString) Composition() map[rune]int {
+	alphabet := self.Alphabet()
+	counts := make([]int, len(alphabet.symbols))
+
+	if alphabet.width != 4 {
+		for i := 0; i < self.length; i++ {
+			counts[self.base(i)]++
+		}
+	} else if self.length > 0 {
+		src, offset := self.packedBytes()
+
+		// the first and last bytes may be shared with bases outside the
+		// string, so deal with them individually
+		first, last := 0, len(src)
+		if offset != 0 {
+			counts[src[0]>>4]++
+			first++
+		}
+		if (offset+self.length)%2 != 0 && last > first {
+			counts[src[last-1]&0x0F]++
+			last--
+		}
+
+		var pairs [256]int
+		for _, b := range src[first:last] {
+			pairs[b]++
+		}
+		for b, n := range pairs {
+			counts[b&0x0F] += n
+			counts[b>>4] += n
+		}
+	}
+
+	result := make(map[rune]int, len(alphabet.symbols)-1)
+	for code, c := range alphabet.symbols[1:] {
+		result[c] = counts[code+1]
+	}
+	return result
+}
+
+/// HammingDistance counts the positions at which two equal-length strings
+/// differ. Symbols are compared exactly, so an ambiguity code never matches
+/// a base. Nibble-packed strings with the same alignment are compared
+/// sixteen bases at a time.
+func HammingDistance(a, b BaseString) (int, error) {
+	if a.length != b.length {
+		return 0, LengthMismatchError{a.length, b.length}
+	}
+	if !a.Alphabet().sharesEncoding(b.Alphabet()) {
+		return 0, IncompatibleAlphabetError{a.Alphabet(), b.Alphabet()}
+	}
+
+	if a.Alphabet().width != 4 || (a.offset+b.offset)%2 != 0 {
+		count := 0
+		for i := 0; i < a.length; i++ {
+			if a.base(i) != b.base(i) {
+				count++
+			}
+		}
+		return count, nil
+	}
+
+	srcA, offsetA := a.packedBytes()
+	srcB, _ := b.packedBytes()
+	count := 0
+	for k := 0; k < (len(srcA)+7)/8; k++ {
+		same := zeroNibbles(loadWord(srcA, k) ^ loadWord(srcB, k))
+		count += bits.OnesCount64(^same & validNibbles(k, offsetA, a.length))
+	}
+	return count, nil
+}
+
+/// zeroNibbles sets the low bit of every nibble in the result whose
+/// corresponding nibble in x is zero.
+func zeroNibbles(x uint64) uint64 {
+	x |= x >> 1
+	x |= x >> 2
+	return ^x & lowNibbleBits
+}
+
+/// loadWord loads the k'th little-endian 64-bit word from src, padding with
+/// zeros if src runs out.
+func loadWord(src []byte, k int) uint64 {
+	start := k * 8
+	if start+8 <= len(src) {
+		return binary.LittleEndian.Uint64(src[start:])
+	}
+	var buf [8]byte
+	copy(buf[:], src[start:])
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+/// validNibbles returns a mask with the low bit set for every nibble in the
+/// k'th word that lies within the nibble range [offset, offset+length).
+func validNibbles(k, offset, length int) uint64 {
+	first := k * 16
+	mask := uint64(lowNibbleBits)
+	if skip := offset - first; skip > 0 {
+		mask <<= uint(4 * skip)
+	}
+	if keep := offset + length - first; keep < 16 {
+		if keep <= 0 {
+			return 0
+		}
+		mask &= lowNibbleBits >> uint(4*(16-keep))
+	}
+	return mask
+}
@@ -0,0 +1,118 @@
+package basestring
+
+import (
+	"strings"
+	"testing"
+)
+
+const countText = "GATTACANNGATTACAGCGCTTTTAAAACCCCGGGGRYATGCGCGCGCGTTAACG"
+
+func naiveCount(s string, c rune) int {
+	return strings.Count(s, string(c))
+}
+
+func Test_CountWorksOnAllSlices(t *testing.T) {
+	s, _ := FromString(countText)
+	for i := 0; i <= len(countText); i++ {
+		for j := i; j <= len(countText); j++ {
+			v := s.Slice(i, j)
+			for _, c := range "ACGTNRY" {
+				expected := naiveCount(countText[i:j], c)
+				if n := v.Count(c); n != expected {
+					t.Fatalf("Expected %d %c in %s, got %d", expected, c, countText[i:j], n)
+				}
+			}
+		}
+	}
+}
+
+func Test_CountOfInvalidBaseIsZero(t *testing.T) {
+	s, _ := FromString(countText)
+	if s.Count('Z') != 0 {
+		t.Error("Expected zero count for invalid base")
+	}
+}
+
+func Test_CompositionWorksOnAllSlices(t *testing.T) {
+	s, _ := FromString(countText)
+	for i := 0; i <= len(countText); i++ {
+		for j := i; j <= len(countText); j++ {
+			composition := s.Slice(i, j).Composition()
+			if len(composition) != 15 {
+				t.Fatalf("Expected a count for every symbol, got %v", composition)
+			}
+			for c, n := range composition {
+				if expected := naiveCount(countText[i:j], c); n != expected {
+					t.Fatalf("Expected %d %c in %s, got %d", expected, c, countText[i:j], n)
+				}
+			}
+		}
+	}
+}
+
+func Test_CompositionOfProtein(t *testing.T) {
+	s, _ := Protein.FromString("MAMAPRTEIN")
+	composition := s.Composition()
+	if composition['M'] != 2 || composition['A'] != 2 || composition['W'] != 0 {
+		t.Errorf("Unexpected composition %v", composition)
+	}
+}
+
+func Test_HammingDistanceWorks(t *testing.T) {
+	a, _ := FromString("GAGCCTACTAACGGGAT")
+	b, _ := FromString("CATCGTAATGACGGCCT")
+	d, err := HammingDistance(a, b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if d != 7 {
+		t.Errorf("Expected distance 7, got %d", d)
+	}
+}
+
+func Test_HammingDistanceWorksOnAllAlignments(t *testing.T) {
+	other := strings.Map(func(c rune) rune {
+		if c == 'A' {
+			return 'C'
+		}
+		return c
+	}, countText)
+
+	a, _ := FromString(countText)
+	b, _ := FromString(other)
+	for i := 0; i <= len(countText); i++ {
+		for j := i; j <= len(countText); j++ {
+			expected := naiveCount(countText[i:j], 'A')
+			for shift := 0; shift < 2 && j+shift <= len(countText); shift++ {
+				d, err := HammingDistance(a.Slice(i, j), b.Slice(i+shift, j+shift))
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err.Error())
+				}
+
+				if shift == 0 && d != expected {
+					t.Fatalf("Expected distance %d for [%d:%d], got %d", expected, i, j, d)
+				}
+
+				if shift == 1 {
+					naive := 0
+					for k := i; k < j; k++ {
+						if countText[k] != other[k+1] {
+							naive++
+						}
+					}
+					if d != naive {
+						t.Fatalf("Expected distance %d, got %d", naive, d)
+					}
+				}
+			}
+		}
+	}
+}
+
+func Test_HammingDistanceRejectsMismatchedLengths(t *testing.T) {
+	a, _ := FromString("GATTACA")
+	b, _ := FromString("GATTAC")
+	if _, err := HammingDistance(a, b); err != (LengthMismatchError{7, 6}) {
+		t.Errorf("Expected LengthMismatchError, got %v", err)
+	}
+}
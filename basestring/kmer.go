@@ -0,0 +1,94 @@
+package basestring
+
+import (
+	"fmt"
+	"iter"
+)
+
+/// MaxK is the longest k-mer that fits in a uint64
+const MaxK = 32
+
+/// kmerCodes maps a nibble value onto the 2-bit code used to pack k-mers:
+/// A, C, G & T are encoded as 0-3, so numeric order matches lexicographic
+/// order, and the complement of a base is 3 minus its code.
+var kmerCodes = [16]byte{
+	0, // invalid
+	2, // G
+	0, // A
+	3, // T
+	1, // C
+}
+
+/// kmerChars maps a 2-bit k-mer code back onto its base
+var kmerChars = [4]byte{'A', 'C', 'G', 'T'}
+
+/// KMers returns an iterator over every k-mer in the string, yielding the
+/// index of each k-mer along with its value packed two bits per base, with
+/// the first base in the most significant bits. Each k-mer is computed from
+/// the previous one with a rolling update rather than being re-packed from
+/// scratch. Windows containing an ambiguous base are skipped. Panics if k is
+/// not in the range [1, MaxK] or the string isn't DNA or RNA.
+func (self BaseString) KMers(k int) iter.Seq2[int, uint64] {
+	return self.kmers(k, false)
+}
+
+/// CanonicalKMers works like KMers, but yields the canonical form of each
+/// k-mer, i.e. the lesser of the k-mer and its reverse complement, so that
+/// a k-mer and its reverse complement are counted as the same thing.
+func (self BaseString) CanonicalKMers(k int) iter.Seq2[int, uint64] {
+	return self.kmers(k, true)
+}
+
+/// DecodeKMer unpacks a k-mer produced by KMers back into DNA bases.
+func DecodeKMer(kmer uint64, k int) string {
+	result := make([]byte, k)
+	for i := k - 1; i >= 0; i-- {
+		result[i] = kmerChars[kmer&0x03]
+		kmer >>= 2
+	}
+	return string(result)
+}
+
+func (self BaseString) kmers(k int, canonical bool) iter.Seq2[int, uint64] {
+	if k < 1 || k > MaxK {
+		panic(fmt.Sprintf("basestring: k-mer length %d out of range [1:%d]", k, MaxK))
+	}
+	alphabet := self.Alphabet()
+	alphabet.mustBeNucleic("KMers")
+
+	mask := uint64(1)<<(2*uint(k)) - 1
+	if k == MaxK {
+		mask = ^uint64(0)
+	}
+	topShift := 2 * uint(k-1)
+
+	return func(yield func(int, uint64) bool) {
+		forward := uint64(0)
+		reverse := uint64(0)
+		run := 0
+		for i := 0; i < self.length; i++ {
+			base := self.base(i)
+			if alphabet.ambiguous(base) {
+				run = 0
+				continue
+			}
+
+			code := uint64(kmerCodes[base])
+			forward = ((forward << 2) | code) & mask
+			reverse = (reverse >> 2) | ((3 - code) << topShift)
+			run++
+
+			if run < k {
+				continue
+			}
+
+			kmer := forward
+			if canonical && reverse < forward {
+				kmer = reverse
+			}
+			if !yield(i-k+1, kmer) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package basestring
+
+import (
+	"testing"
+)
+
+func Test_KMersYieldsEveryWindow(t *testing.T) {
+	text := "GATTACAGATTACA"
+	s, _ := FromString(text)
+	for k := 1; k <= 5; k++ {
+		count := 0
+		for i, kmer := range s.KMers(k) {
+			if i != count {
+				t.Errorf("Expected index %d, got %d", count, i)
+			}
+			if DecodeKMer(kmer, k) != text[i:i+k] {
+				t.Errorf("Expected %s at %d, got %s", text[i:i+k], i, DecodeKMer(kmer, k))
+			}
+			count++
+		}
+		if count != len(text)-k+1 {
+			t.Errorf("Expected %d %d-mers, got %d", len(text)-k+1, k, count)
+		}
+	}
+}
+
+func Test_KMersAreLexicographicallyOrdered(t *testing.T) {
+	s, _ := FromString("ACGTACGT")
+	expected := []uint64{0, 1, 2, 3}
+	i := 0
+	for _, kmer := range s.Slice(0, 4).KMers(1) {
+		if kmer != expected[i] {
+			t.Errorf("Expected %d, got %d", expected[i], kmer)
+		}
+		i++
+	}
+
+	acgt, _ := FromString("ACGT")
+	for _, kmer := range acgt.KMers(4) {
+		if kmer != 0x1B {
+			t.Errorf("Expected 0x1B, got %#x", kmer)
+		}
+	}
+}
+
+func Test_KMersSkipsAmbiguousWindows(t *testing.T) {
+	s, _ := FromString("GATNTACARGATT")
+	result := map[int]string{}
+	for i, kmer := range s.KMers(3) {
+		result[i] = DecodeKMer(kmer, 3)
+	}
+
+	expected := map[int]string{0: "GAT", 4: "TAC", 5: "ACA", 9: "GAT", 10: "ATT"}
+	if len(result) != len(expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	for i, kmer := range expected {
+		if result[i] != kmer {
+			t.Errorf("Expected %s at %d, got %s", kmer, i, result[i])
+		}
+	}
+}
+
+func Test_CanonicalKMersMatchReverseComplement(t *testing.T) {
+	text := "GATTACAGGCTTAACGTAGGCTAAGCTAGCATCGACTACGATCAGCTAGCATCGACGA"
+	s, _ := FromString(text)
+	rc := s.ReverseComplement()
+
+	for _, k := range []int{1, 2, 7, 16, 31, 32} {
+		if k > len(text) {
+			continue
+		}
+		forward := map[uint64]int{}
+		for _, kmer := range s.CanonicalKMers(k) {
+			forward[kmer]++
+		}
+		reverse := map[uint64]int{}
+		for _, kmer := range rc.CanonicalKMers(k) {
+			reverse[kmer]++
+		}
+
+		if len(forward) != len(reverse) {
+			t.Fatalf("Expected the same k-mers on both strands for k = %d", k)
+		}
+		for kmer, n := range forward {
+			if reverse[kmer] != n {
+				t.Errorf("Expected %s %d times, got %d", DecodeKMer(kmer, k), n, reverse[kmer])
+			}
+		}
+	}
+}
+
+func Test_CanonicalKMerIsTheLesserStrand(t *testing.T) {
+	s, _ := FromString("TTG")
+	for _, kmer := range s.CanonicalKMers(3) {
+		if DecodeKMer(kmer, 3) != "CAA" {
+			t.Errorf("Expected CAA, got %s", DecodeKMer(kmer, 3))
+		}
+	}
+}
+
+func Test_KMersStopsWhenAsked(t *testing.T) {
+	s, _ := FromString("GATTACAGATTACA")
+	count := 0
+	for range s.KMers(2) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("Expected to stop after 3, got %d", count)
+	}
+}
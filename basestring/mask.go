@@ -0,0 +1,72 @@
+package basestring
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+)
+
+/// IsMasked returns true if the base at index i is soft-masked, i.e. it was
+/// lowercase in the original text. Panics if i is out of range.
+func (self BaseString) IsMasked(i int) bool {
+	if i < 0 || i >= self.length {
+		panic(fmt.Sprintf("basestring: index %d out of range [0:%d]", i, self.length))
+	}
+	return inIntervals(self.mask, self.maskStart+i)
+}
+
+/// MaskedIntervals returns an iterator over the runs of soft-masked bases in
+/// the string, in order.
+func (self BaseString) MaskedIntervals() iter.Seq[Interval] {
+	return func(yield func(Interval) bool) {
+		start := self.maskStart
+		end := self.maskStart + self.length
+		n := sort.Search(len(self.mask), func(k int) bool {
+			return self.mask[k].End > start
+		})
+
+		for _, interval := range self.mask[n:] {
+			if interval.Start >= end {
+				return
+			}
+			clipped := Interval{
+				Start: max(interval.Start, start) - start,
+				End:   min(interval.End, end) - start,
+			}
+			if !yield(clipped) {
+				return
+			}
+		}
+	}
+}
+
+/// Unmasked returns a copy of the string with any soft-masking removed. The
+/// bases themselves are shared with the original.
+func (self BaseString) Unmasked() BaseString {
+	self.mask = nil
+	self.maskStart = 0
+	return self
+}
+
+/// maskedIntervals collects the string's masked intervals into a slice
+func (self BaseString) maskedIntervals() []Interval {
+	result := []Interval{}
+	for interval := range self.MaskedIntervals() {
+		result = append(result, interval)
+	}
+	return result
+}
+
+/// reverseIntervals maps a set of intervals on a string of the given length
+/// onto the same positions in the reversed string.
+func reverseIntervals(intervals []Interval, length int) []Interval {
+	n := len(intervals)
+	result := make([]Interval, n)
+	for i, interval := range intervals {
+		result[n-1-i] = Interval{
+			Start: length - interval.End,
+			End:   length - interval.Start,
+		}
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package basestring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"reflect"
+	"testing"
+)
+
+const maskedText = "GATtacaGATTAnnnnACAgattaca"
+
+func Test_SoftMaskingRoundTrips(t *testing.T) {
+	s, err := FromString(maskedText)
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+	if s.String() != maskedText {
+		t.Errorf("Expected \"%s\", got \"%s\"", maskedText, s.String())
+	}
+
+	for i, c := range maskedText {
+		expected := 'a' <= c && c <= 'z'
+		if s.IsMasked(i) != expected {
+			t.Errorf("Expected IsMasked(%d) == %v", i, expected)
+		}
+	}
+
+	if s.At(3) != 'T' {
+		t.Errorf("Expected At() to ignore masking, got %c", s.At(3))
+	}
+}
+
+func Test_MaskedIntervalsAreReported(t *testing.T) {
+	s, _ := FromString(maskedText)
+	expected := []Interval{{3, 7}, {12, 16}, {19, 26}}
+	if !reflect.DeepEqual(s.maskedIntervals(), expected) {
+		t.Errorf("Expected %v, got %v", expected, s.maskedIntervals())
+	}
+}
+
+func Test_MaskingSurvivesSlicing(t *testing.T) {
+	s, _ := FromString(maskedText)
+	for i := 0; i <= len(maskedText); i++ {
+		for j := i; j <= len(maskedText); j++ {
+			if v := s.Slice(i, j).String(); v != maskedText[i:j] {
+				t.Fatalf("Expected \"%s\", got \"%s\"", maskedText[i:j], v)
+			}
+		}
+	}
+
+	v := s.Slice(5, 14)
+	expected := []Interval{{0, 2}, {7, 9}}
+	if !reflect.DeepEqual(v.maskedIntervals(), expected) {
+		t.Errorf("Expected %v, got %v", expected, v.maskedIntervals())
+	}
+}
+
+func Test_MaskingSurvivesReverseComplement(t *testing.T) {
+	s, _ := FromString("GATtacaNNc")
+	if rc := s.ReverseComplement().String(); rc != "gNNtgtaATC" {
+		t.Errorf("Expected \"gNNtgtaATC\", got \"%s\"", rc)
+	}
+	if c := s.Slice(1, 9).Complement().String(); c != "TAatgtNN" {
+		t.Errorf("Expected \"TAatgtNN\", got \"%s\"", c)
+	}
+}
+
+func Test_UnmaskedDropsMasking(t *testing.T) {
+	s, _ := FromString(maskedText)
+	if u := s.Slice(2, 8).Unmasked().String(); u != "TTACAG" {
+		t.Errorf("Expected \"TTACAG\", got \"%s\"", u)
+	}
+}
+
+func Test_BuilderRecordsMasking(t *testing.T) {
+	var b Builder
+	b.AppendString("GATta")
+	s := b.Build()
+	b.AppendString("caGA")
+	b.Append('t')
+	if err := b.AppendString("taXX"); err == nil {
+		t.Fatal("Expected append to fail")
+	}
+	b.AppendString("TA")
+
+	if s.String() != "GATta" {
+		t.Errorf("Expected \"GATta\", got \"%s\"", s.String())
+	}
+	if r := b.Build().String(); r != "GATtacaGAtTA" {
+		t.Errorf("Expected \"GATtacaGAtTA\", got \"%s\"", r)
+	}
+}
+
+func Test_MaskingSurvivesSerialization(t *testing.T) {
+	s, _ := FromString(maskedText)
+	for _, v := range []BaseString{s, s.Slice(5, 14), s.ReverseComplement()} {
+		data, _ := v.MarshalBinary()
+		var result BaseString
+		if err := result.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Unmarshal failed: %s", err.Error())
+		}
+		if result.String() != v.String() {
+			t.Errorf("Expected \"%s\", got \"%s\"", v.String(), result.String())
+		}
+	}
+}
+
+func Test_VersionOneDataIsStillReadable(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("BSTR")
+	buf.WriteByte(1)
+	buf.WriteByte(1)
+	binary.Write(&buf, binary.LittleEndian, uint64(7))
+	buf.Write([]byte{0x21, 0x33, 0x42, 0x02})
+	binary.Write(&buf, binary.LittleEndian, crc32.ChecksumIEEE(buf.Bytes()))
+
+	var s BaseString
+	if err := s.UnmarshalBinary(buf.Bytes()); err != nil {
+		t.Fatalf("Unmarshal failed: %s", err.Error())
+	}
+	if s.String() != "GATTACA" {
+		t.Errorf("Expected \"GATTACA\", got \"%s\"", s.String())
+	}
+}
+
+func Test_MaskingSurvivesTwoBitConversion(t *testing.T) {
+	s, _ := FromString(maskedText)
+	if tb := s.ToTwoBit().String(); tb != maskedText {
+		t.Errorf("Expected \"%s\", got \"%s\"", maskedText, tb)
+	}
+	if tb := s.Slice(5, 14).ToTwoBit().ToBaseString().String(); tb != maskedText[5:14] {
+		t.Errorf("Expected \"%s\", got \"%s\"", maskedText[5:14], tb)
+	}
+}
@@ -0,0 +1,497 @@
+package basestring
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+/// Interval is a half-open range of base indices [Start, End)
+type Interval struct {
+	Start int
+	End   int
+}
+
+/// Length returns the number of bases covered by the interval
+func (self Interval) Length() int {
+	return self.End - self.Start
+}
+
+/// TwoBitString is a string of bases packed four to a byte, using the same
+/// layout as the UCSC .2bit format: T, C, A & G are encoded as 0-3, with the
+/// first base in the most significant bits of each byte. Runs of N and
+/// lowercase (soft-masked) bases can't be represented in two bits, so they
+/// are stored in side tables of intervals instead.
+type TwoBitString struct {
+	packed     []byte
+	length     int
+	nBlocks    []Interval
+	maskBlocks []Interval
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// twoBitCodes maps a nibble value onto its .2bit code. Ambiguous bases are
+/// all stored as T (i.e. zero) and recorded in the N block table.
+var twoBitCodes = [16]byte{
+	0, // invalid
+	3, // G
+	2, // A
+	0, // T
+	1, // C
+}
+
+/// twoBitChars maps a .2bit code back onto the base it encodes
+var twoBitChars = [4]byte{'T', 'C', 'A', 'G'}
+
+/// TwoBitFromString creates a 2-bit packed string from text. Lowercase bases
+/// are recorded as soft-masked. As with UCSC faToTwoBit, any ambiguity code
+/// is stored as an N, as .2bit has no way of representing anything finer.
+func TwoBitFromString(s string) (TwoBitString, error) {
+	str := newTwoBitString(len(s))
+
+	i := 0
+	for _, c := range s {
+		if 'a' <= c && c <= 'z' {
+			str.maskBlocks = appendToRun(str.maskBlocks, i)
+			c = c - 'a' + 'A'
+		}
+
+		base, err := toBaseChar(c)
+		if err != nil {
+			return TwoBitString{}, err
+		}
+
+		if DNA.ambiguous(base) {
+			str.nBlocks = appendToRun(str.nBlocks, i)
+		} else {
+			str.setCode(i, twoBitCodes[base])
+		}
+		i++
+	}
+	str.length = i
+	return str, nil
+}
+
+/// ToTwoBit packs the string into the 2-bit representation. Ambiguous bases
+/// are stored as N, and soft-masking is preserved. Panics if the string isn't
+/// DNA or RNA.
+func (self BaseString) ToTwoBit() TwoBitString {
+	alphabet := self.Alphabet()
+	alphabet.mustBeNucleic("ToTwoBit")
+	str := newTwoBitString(self.length)
+	for i := 0; i < self.length; i++ {
+		base := self.base(i)
+		if alphabet.ambiguous(base) {
+			str.nBlocks = appendToRun(str.nBlocks, i)
+		} else {
+			str.setCode(i, twoBitCodes[base])
+		}
+	}
+	str.maskBlocks = self.maskedIntervals()
+	return str
+}
+
+/// ToBaseString unpacks the string into a DNA BaseString, restoring the N
+/// runs and soft-masking.
+func (self TwoBitString) ToBaseString() BaseString {
+	str := newBaseString(DNA, self.length)
+
+	for i := 0; i < self.length; i++ {
+		base, _ := toBaseChar(rune(twoBitChars[self.code(i)]))
+		str.setCode(i, base)
+	}
+
+	n, _ := toBaseChar('N')
+	for _, block := range self.nBlocks {
+		for i := block.Start; i < block.End; i++ {
+			str.setCode(i, n)
+		}
+	}
+	str.mask = self.MaskBlocks()
+	return str
+}
+
+/// Returns the length of the string in bases
+func (self TwoBitString) Length() int {
+	return self.length
+}
+
+/// At decodes the base at index i, returning N for bases in an N block and
+/// lowercase for soft-masked bases. Panics if i is out of range.
+func (self TwoBitString) At(i int) rune {
+	if i < 0 || i >= self.length {
+		panic(fmt.Sprintf("basestring: index %d out of range [0:%d]", i, self.length))
+	}
+
+	c := rune(twoBitChars[self.code(i)])
+	if inIntervals(self.nBlocks, i) {
+		c = 'N'
+	}
+	if inIntervals(self.maskBlocks, i) {
+		c = c - 'A' + 'a'
+	}
+	return c
+}
+
+/// String decodes the entire string back into text, including N runs and
+/// soft-masking.
+func (self TwoBitString) String() string {
+	result := make([]byte, self.length)
+	for i := 0; i < self.length; i++ {
+		result[i] = twoBitChars[self.code(i)]
+	}
+	for _, block := range self.nBlocks {
+		for i := block.Start; i < block.End; i++ {
+			result[i] = 'N'
+		}
+	}
+	for _, block := range self.maskBlocks {
+		for i := block.Start; i < block.End; i++ {
+			result[i] = result[i] - 'A' + 'a'
+		}
+	}
+	return string(result)
+}
+
+/// NBlocks returns the runs of N in the string, in order.
+func (self TwoBitString) NBlocks() []Interval {
+	return append([]Interval(nil), self.nBlocks...)
+}
+
+/// MaskBlocks returns the runs of soft-masked bases in the string, in order.
+func (self TwoBitString) MaskBlocks() []Interval {
+	return append([]Interval(nil), self.maskBlocks...)
+}
+
+func newTwoBitString(n int) TwoBitString {
+	return TwoBitString{
+		packed:     make([]byte, (n+3)/4),
+		length:     n,
+		nBlocks:    []Interval{},
+		maskBlocks: []Interval{},
+	}
+}
+
+/// code fetches the raw 2-bit value of the base at index i
+func (self TwoBitString) code(i int) byte {
+	shift := uint(6 - 2*(i%4))
+	return (self.packed[i/4] >> shift) & 0x03
+}
+
+/// setCode writes a raw 2-bit value into the string at index i
+func (self *TwoBitString) setCode(i int, code byte) {
+	shift := uint(6 - 2*(i%4))
+	b := self.packed[i/4] &^ (0x03 << shift)
+	self.packed[i/4] = b | (code << shift)
+}
+
+/// appendToRun adds index i to a list of runs, either by extending the last
+/// run or starting a new one. Indices must be added in ascending order.
+func appendToRun(runs []Interval, i int) []Interval {
+	if n := len(runs); n > 0 && runs[n-1].End == i {
+		runs[n-1].End++
+		return runs
+	}
+	return append(runs, Interval{i, i + 1})
+}
+
+/// inIntervals checks whether index i lies inside any of a sorted list of
+/// non-overlapping intervals.
+func inIntervals(intervals []Interval, i int) bool {
+	n := sort.Search(len(intervals), func(k int) bool {
+		return intervals[k].End > i
+	})
+	return n < len(intervals) && intervals[n].Start <= i
+}
+
+// ----------------------------------------------------------------------------
+// .2bit file support
+// ----------------------------------------------------------------------------
+
+const twoBitSignature = 0x1A412743
+
+/// TwoBitFormatError describes a malformed .2bit file
+type TwoBitFormatError string
+
+func (self TwoBitFormatError) Error() string {
+	return fmt.Sprintf("Invalid .2bit file: %s", string(self))
+}
+
+/// UnknownSequenceError is returned when asking a .2bit file for a sequence
+/// it doesn't contain.
+type UnknownSequenceError string
+
+func (self UnknownSequenceError) Error() string {
+	return fmt.Sprintf("No such sequence: %s", string(self))
+}
+
+/// TwoBitRecord is a named sequence in a .2bit file
+type TwoBitRecord struct {
+	Name     string
+	Sequence TwoBitString
+}
+
+/// TwoBitFile provides random access to the sequences in a .2bit file.
+type TwoBitFile struct {
+	reader  io.ReaderAt
+	order   binary.ByteOrder
+	names   []string
+	offsets map[string]int64
+}
+
+/// OpenTwoBit reads the header and sequence index of a .2bit file. Sequences
+/// are only read on demand.
+func OpenTwoBit(r io.ReaderAt) (*TwoBitFile, error) {
+	in := bufio.NewReader(io.NewSectionReader(r, 0, math.MaxInt64))
+
+	var header [16]byte
+	if _, err := io.ReadFull(in, header[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	var order binary.ByteOrder
+	switch {
+	case binary.LittleEndian.Uint32(header[0:]) == twoBitSignature:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(header[0:]) == twoBitSignature:
+		order = binary.BigEndian
+	default:
+		return nil, TwoBitFormatError("bad signature")
+	}
+
+	version := order.Uint32(header[4:])
+	if version > 1 {
+		return nil, TwoBitFormatError(fmt.Sprintf("unsupported version %d", version))
+	}
+
+	// the count is untrusted, so the index grows as entries are actually
+	// read rather than being allocated up front
+	count := int(order.Uint32(header[8:]))
+	file := &TwoBitFile{
+		reader:  r,
+		order:   order,
+		names:   []string{},
+		offsets: map[string]int64{},
+	}
+
+	for i := 0; i < count; i++ {
+		nameSize, err := in.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		name := make([]byte, nameSize)
+		if _, err := io.ReadFull(in, name); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+
+		var offset int64
+		if version == 0 {
+			var buf [4]byte
+			if _, err := io.ReadFull(in, buf[:]); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			offset = int64(order.Uint32(buf[:]))
+		} else {
+			var buf [8]byte
+			if _, err := io.ReadFull(in, buf[:]); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			offset = int64(order.Uint64(buf[:]))
+		}
+
+		file.names = append(file.names, string(name))
+		file.offsets[string(name)] = offset
+	}
+
+	return file, nil
+}
+
+/// Names returns the names of the sequences in the file, in file order.
+func (self *TwoBitFile) Names() []string {
+	return append([]string(nil), self.names...)
+}
+
+/// Read loads the named sequence from the file.
+func (self *TwoBitFile) Read(name string) (TwoBitString, error) {
+	offset, ok := self.offsets[name]
+	if !ok {
+		return TwoBitString{}, UnknownSequenceError(name)
+	}
+
+	in := bufio.NewReader(io.NewSectionReader(self.reader, offset, math.MaxInt64-offset))
+	readUint32 := func() (int, error) {
+		var buf [4]byte
+		if _, err := io.ReadFull(in, buf[:]); err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		return int(self.order.Uint32(buf[:])), nil
+	}
+
+	// the counts and lengths are untrusted, so everything is grown as the
+	// data actually arrives rather than allocated up front
+	readBlocks := func(length int) ([]Interval, error) {
+		count, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		if count > length {
+			return nil, TwoBitFormatError(
+				fmt.Sprintf("too many blocks (%d) in %s", count, name))
+		}
+
+		blocks := []Interval{}
+		for i := 0; i < count; i++ {
+			start, err := readUint32()
+			if err != nil {
+				return nil, err
+			}
+			blocks = append(blocks, Interval{Start: start})
+		}
+		for i := range blocks {
+			size, err := readUint32()
+			if err != nil {
+				return nil, err
+			}
+			blocks[i].End = blocks[i].Start + size
+			if blocks[i].End > length {
+				return nil, TwoBitFormatError(
+					fmt.Sprintf("block [%d:%d] past end of %s",
+						blocks[i].Start, blocks[i].End, name))
+			}
+			if i > 0 && blocks[i].Start < blocks[i-1].End {
+				return nil, TwoBitFormatError(
+					fmt.Sprintf("block [%d:%d] out of order or overlapping in %s",
+						blocks[i].Start, blocks[i].End, name))
+			}
+		}
+		return blocks, nil
+	}
+
+	length, err := readUint32()
+	if err != nil {
+		return TwoBitString{}, err
+	}
+
+	str := TwoBitString{length: length}
+	if str.nBlocks, err = readBlocks(length); err != nil {
+		return TwoBitString{}, err
+	}
+	if str.maskBlocks, err = readBlocks(length); err != nil {
+		return TwoBitString{}, err
+	}
+
+	// reserved
+	if _, err = readUint32(); err != nil {
+		return TwoBitString{}, err
+	}
+
+	size := int64(length+3) / 4
+	if str.packed, err = io.ReadAll(io.LimitReader(in, size)); err != nil {
+		return TwoBitString{}, err
+	}
+	if int64(len(str.packed)) != size {
+		return TwoBitString{}, io.ErrUnexpectedEOF
+	}
+
+	return str, nil
+}
+
+/// WriteTwoBit writes a set of named sequences out in .2bit format. The
+/// 64-bit offset variant of the format is used only if the file is too
+/// large to index with 32-bit offsets.
+func WriteTwoBit(w io.Writer, records ...TwoBitRecord) error {
+	recordSize := func(s TwoBitString) int64 {
+		return int64(16 + 8*len(s.nBlocks) + 8*len(s.maskBlocks) + len(s.packed))
+	}
+
+	indexSize := func(offsetSize int) int64 {
+		size := int64(0)
+		for _, r := range records {
+			size += int64(1 + len(r.Name) + offsetSize)
+		}
+		return size
+	}
+
+	for _, r := range records {
+		if len(r.Name) > math.MaxUint8 {
+			return fmt.Errorf("Sequence name too long for .2bit: %s", r.Name)
+		}
+		if int64(r.Sequence.length) > math.MaxUint32 {
+			return fmt.Errorf("Sequence too long for .2bit: %s", r.Name)
+		}
+	}
+
+	total := 16 + indexSize(4)
+	for _, r := range records {
+		total += recordSize(r.Sequence)
+	}
+
+	version := uint32(0)
+	offsetSize := 4
+	if total > math.MaxUint32 {
+		version = 1
+		offsetSize = 8
+	}
+
+	out := bufio.NewWriter(w)
+	order := binary.LittleEndian
+	var buf [8]byte
+	writeUint32 := func(n uint32) {
+		order.PutUint32(buf[:], n)
+		out.Write(buf[:4])
+	}
+
+	writeUint32(twoBitSignature)
+	writeUint32(version)
+	writeUint32(uint32(len(records)))
+	writeUint32(0)
+
+	offset := 16 + indexSize(offsetSize)
+	for _, r := range records {
+		out.WriteByte(byte(len(r.Name)))
+		out.WriteString(r.Name)
+		if version == 0 {
+			writeUint32(uint32(offset))
+		} else {
+			order.PutUint64(buf[:], uint64(offset))
+			out.Write(buf[:])
+		}
+		offset += recordSize(r.Sequence)
+	}
+
+	writeBlocks := func(blocks []Interval) {
+		writeUint32(uint32(len(blocks)))
+		for _, b := range blocks {
+			writeUint32(uint32(b.Start))
+		}
+		for _, b := range blocks {
+			writeUint32(uint32(b.Length()))
+		}
+	}
+
+	for _, r := range records {
+		writeUint32(uint32(r.Sequence.length))
+		writeBlocks(r.Sequence.nBlocks)
+		writeBlocks(r.Sequence.maskBlocks)
+		writeUint32(0)
+		out.Write(r.Sequence.packed)
+	}
+
+	return out.Flush()
+}
+
+/// unexpectedEOF converts a premature EOF into io.ErrUnexpectedEOF, so that
+/// a truncated file isn't mistaken for a cleanly finished one.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
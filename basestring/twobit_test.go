@@ -0,0 +1,217 @@
+package basestring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func Test_TwoBitRoundTripsNsAndSoftMasking(t *testing.T) {
+	text := "NNGATTacagattNNnnACGT"
+	s, err := TwoBitFromString(text)
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+
+	if s.String() != text {
+		t.Errorf("Expected \"%s\", got \"%s\"", text, s.String())
+	}
+
+	for i, c := range text {
+		if s.At(i) != c {
+			t.Errorf("Expected %c at %d, got %c", c, i, s.At(i))
+		}
+	}
+
+	expectedN := []Interval{{0, 2}, {13, 17}}
+	if !reflect.DeepEqual(s.NBlocks(), expectedN) {
+		t.Errorf("Expected N blocks %v, got %v", expectedN, s.NBlocks())
+	}
+
+	expectedMask := []Interval{{6, 13}, {15, 17}}
+	if !reflect.DeepEqual(s.MaskBlocks(), expectedMask) {
+		t.Errorf("Expected mask blocks %v, got %v", expectedMask, s.MaskBlocks())
+	}
+}
+
+func Test_TwoBitUsesUcscPacking(t *testing.T) {
+	s, _ := TwoBitFromString("TCAGG")
+	e := []byte{0x1B, 0xC0}
+	if bytes.Compare(s.packed, e) != 0 {
+		t.Errorf("expected %#v, got %#v", e, s.packed)
+	}
+}
+
+func Test_TwoBitStoresAmbiguityCodesAsN(t *testing.T) {
+	s, err := TwoBitFromString("GARTYCA")
+	if err != nil {
+		t.Fatalf("Conversion failed: %s", err.Error())
+	}
+	if s.String() != "GANTNCA" {
+		t.Errorf("Expected \"GANTNCA\", got \"%s\"", s.String())
+	}
+}
+
+func Test_ConvertingBetweenBaseStringAndTwoBit(t *testing.T) {
+	b, _ := FromString("GATTACANNNNNCATRG")
+	s := b.ToTwoBit()
+	if s.String() != "GATTACANNNNNCATNG" {
+		t.Errorf("Expected \"GATTACANNNNNCATNG\", got \"%s\"", s.String())
+	}
+
+	if s.ToBaseString().String() != "GATTACANNNNNCATNG" {
+		t.Errorf("Expected \"GATTACANNNNNCATNG\", got \"%s\"",
+			s.ToBaseString().String())
+	}
+}
+
+func Test_TwoBitFileRoundTrips(t *testing.T) {
+	a, _ := TwoBitFromString("NNGATTacagattNNnnACGT")
+	b, _ := TwoBitFromString("CAT")
+	c, _ := TwoBitFromString("")
+
+	var buf bytes.Buffer
+	err := WriteTwoBit(&buf,
+		TwoBitRecord{"chrA", a},
+		TwoBitRecord{"chrB", b},
+		TwoBitRecord{"chrC", c})
+	if err != nil {
+		t.Fatalf("Write failed: %s", err.Error())
+	}
+
+	file, err := OpenTwoBit(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Open failed: %s", err.Error())
+	}
+
+	expectedNames := []string{"chrA", "chrB", "chrC"}
+	if !reflect.DeepEqual(file.Names(), expectedNames) {
+		t.Errorf("Expected names %v, got %v", expectedNames, file.Names())
+	}
+
+	for _, e := range []TwoBitRecord{{"chrB", b}, {"chrA", a}, {"chrC", c}} {
+		s, err := file.Read(e.Name)
+		if err != nil {
+			t.Fatalf("Read failed: %s", err.Error())
+		}
+		if s.String() != e.Sequence.String() {
+			t.Errorf("Expected \"%s\", got \"%s\"", e.Sequence.String(), s.String())
+		}
+	}
+
+	if _, err := file.Read("chrZ"); err != UnknownSequenceError("chrZ") {
+		t.Errorf("Expected UnknownSequenceError, got %v", err)
+	}
+}
+
+func Test_TwoBitFileReadsBigEndian(t *testing.T) {
+	data := []byte{
+		0x1A, 0x41, 0x27, 0x43, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0,
+		1, 'x', 0, 0, 0, 22,
+		0, 0, 0, 5, // length
+		0, 0, 0, 1, 0, 0, 0, 4, 0, 0, 0, 1, // N block
+		0, 0, 0, 0, // mask blocks
+		0, 0, 0, 0, // reserved
+		0x1B, 0xC0,
+	}
+
+	file, err := OpenTwoBit(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Open failed: %s", err.Error())
+	}
+
+	s, err := file.Read("x")
+	if err != nil {
+		t.Fatalf("Read failed: %s", err.Error())
+	}
+
+	if s.String() != "TCAGN" {
+		t.Errorf("Expected \"TCAGN\", got \"%s\"", s.String())
+	}
+}
+
+func Test_TwoBitFileRejectsGarbage(t *testing.T) {
+	_, err := OpenTwoBit(bytes.NewReader([]byte(">chr1\nGATTACA\nGATTACA\n")))
+	if _, ok := err.(TwoBitFormatError); !ok {
+		t.Errorf("Expected TwoBitFormatError, got %v", err)
+	}
+}
+
+func Test_TruncatedTwoBitFileFails(t *testing.T) {
+	s, _ := TwoBitFromString("GATTACAGATTACA")
+	var buf bytes.Buffer
+	WriteTwoBit(&buf, TwoBitRecord{"chr1", s})
+
+	data := buf.Bytes()[:buf.Len()-2]
+	file, err := OpenTwoBit(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Open failed: %s", err.Error())
+	}
+
+	if _, err := file.Read("chr1"); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func Test_TwoBitFileWithShortHeaderFails(t *testing.T) {
+	s, _ := TwoBitFromString("GATTACA")
+	var buf bytes.Buffer
+	WriteTwoBit(&buf, TwoBitRecord{"chr1", s})
+
+	if _, err := OpenTwoBit(bytes.NewReader(buf.Bytes()[:10])); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func Test_TwoBitFileWithHugeCountFailsCleanly(t *testing.T) {
+	s, _ := TwoBitFromString("GATTACA")
+	var buf bytes.Buffer
+	WriteTwoBit(&buf, TwoBitRecord{"chr1", s})
+
+	// claim ~4 billion sequences; the index runs out long before that
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint32(data[8:], 0xfffffff0)
+	if _, err := OpenTwoBit(bytes.NewReader(data)); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func Test_TwoBitFileWithHugeLengthFailsCleanly(t *testing.T) {
+	s, _ := TwoBitFromString("GATTACA")
+	var buf bytes.Buffer
+	WriteTwoBit(&buf, TwoBitRecord{"chr1", s})
+
+	// the record follows the 16 byte header and the 9 byte index entry
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint32(data[25:], 0xfffffff0)
+	file, err := OpenTwoBit(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Open failed: %s", err.Error())
+	}
+	if _, err := file.Read("chr1"); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func Test_TwoBitFileRejectsOverlappingBlocks(t *testing.T) {
+	s, _ := TwoBitFromString("GANNACANNNA")
+	var buf bytes.Buffer
+	WriteTwoBit(&buf, TwoBitRecord{"chr1", s})
+
+	// N blocks start at [2, 7] with sizes [2, 3]; move the second back over
+	// the first
+	data := buf.Bytes()
+	if binary.LittleEndian.Uint32(data[29:]) != 2 || binary.LittleEndian.Uint32(data[37:]) != 7 {
+		t.Fatalf("Unexpected block layout % x", data[25:53])
+	}
+	binary.LittleEndian.PutUint32(data[37:], 3)
+
+	file, _ := OpenTwoBit(bytes.NewReader(data))
+	if _, err := file.Read("chr1"); err == nil {
+		t.Error("Expected an error")
+	} else if _, ok := err.(TwoBitFormatError); !ok {
+		t.Errorf("Expected TwoBitFormatError, got %v", err)
+	}
+}
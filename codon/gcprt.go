@@ -0,0 +1,191 @@
+package codon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/// GcPrtError describes a problem parsing a gc.prt file
+type GcPrtError struct {
+	Line int
+	Msg  string
+}
+
+func (self GcPrtError) Error() string {
+	return fmt.Sprintf("gc.prt line %d: %s", self.Line, self.Msg)
+}
+
+/// gcToken is a lexical token from a gc.prt file. Quoted strings have their
+/// quotes stripped and quoted is set.
+type gcToken struct {
+	text   string
+	quoted bool
+	line   int
+}
+
+/// ReadGcPrt parses genetic codes from text in the NCBI gc.prt format, i.e.
+///
+///    Genetic-code-table ::= {
+///     {
+///      name "Standard" ,
+///      name "SGC0" ,
+///      id 1 ,
+///      ncbieaa  "FFLLSSSSYY**CC*W...",
+///      sncbieaa "---M------**--*-..."
+///     },
+///     ...
+///    }
+///
+/// The first name of each table is used as its name. Comments (starting with
+/// "--") are ignored.
+func ReadGcPrt(reader io.Reader) ([]*GeneticCode, error) {
+	tokens, err := tokenizeGcPrt(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*GeneticCode{}
+	depth := 0
+	var fields map[string]string
+	var start int
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.quoted:
+			continue
+
+		case tok.text == "{":
+			depth++
+			if depth == 2 {
+				fields = map[string]string{}
+				start = tok.line
+			}
+
+		case tok.text == "}":
+			if depth == 0 {
+				return nil, GcPrtError{tok.line, "unbalanced '}'"}
+			}
+			if depth == 2 {
+				code, err := gcPrtCode(fields, start)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, code)
+			}
+			depth--
+
+		case depth == 2 && tok.text != ",":
+			if i+1 >= len(tokens) {
+				return nil, GcPrtError{tok.line, fmt.Sprintf("missing value for %s", tok.text)}
+			}
+			if _, ok := fields[tok.text]; !ok {
+				fields[tok.text] = tokens[i+1].text
+			}
+			i++
+		}
+	}
+
+	if depth != 0 {
+		return nil, GcPrtError{tokens[len(tokens)-1].line, "unexpected end of file"}
+	}
+	return result, nil
+}
+
+/// gcPrtCode builds a genetic code from the fields of a gc.prt table
+func gcPrtCode(fields map[string]string, line int) (*GeneticCode, error) {
+	for _, key := range []string{"id", "ncbieaa", "sncbieaa"} {
+		if _, ok := fields[key]; !ok {
+			return nil, GcPrtError{line, fmt.Sprintf("table is missing %s", key)}
+		}
+	}
+
+	id, err := strconv.Atoi(fields["id"])
+	if err != nil {
+		return nil, GcPrtError{line, fmt.Sprintf("bad id %q", fields["id"])}
+	}
+
+	// names may be wrapped onto several lines, but the tables are only
+	// meaningful without the whitespace
+	aas := strings.ReplaceAll(fields["ncbieaa"], " ", "")
+	starts := strings.ReplaceAll(fields["sncbieaa"], " ", "")
+	code, err := NewGeneticCode(id, fields["name"], aas, starts)
+	if err != nil {
+		return nil, GcPrtError{line, err.Error()}
+	}
+	return code, nil
+}
+
+/// tokenizeGcPrt splits gc.prt text into braces, commas, words and quoted
+/// strings. Quoted strings may span lines, in which case the line breaks
+/// (and surrounding whitespace) are collapsed into a single space.
+func tokenizeGcPrt(reader io.Reader) ([]gcToken, error) {
+	tokens := []gcToken{}
+	var quoted *strings.Builder
+	quoteLine := 0
+
+	s := bufio.NewScanner(reader)
+	line := 0
+	for s.Scan() {
+		line++
+		text := s.Text()
+
+		if quoted != nil {
+			quoted.WriteByte(' ')
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)
+		}
+
+		for len(text) > 0 {
+			if quoted != nil {
+				end := strings.IndexByte(text, '"')
+				if end < 0 {
+					quoted.WriteString(strings.TrimRightFunc(text, unicode.IsSpace))
+					break
+				}
+				quoted.WriteString(text[:end])
+				tokens = append(tokens, gcToken{quoted.String(), true, quoteLine})
+				quoted = nil
+				text = text[end+1:]
+				continue
+			}
+
+			text = strings.TrimLeftFunc(text, unicode.IsSpace)
+			switch {
+			case len(text) == 0:
+
+			case strings.HasPrefix(text, "--"):
+				text = ""
+
+			case text[0] == '"':
+				quoted = &strings.Builder{}
+				quoteLine = line
+				text = text[1:]
+
+			case text[0] == '{' || text[0] == '}' || text[0] == ',':
+				tokens = append(tokens, gcToken{text[:1], false, line})
+				text = text[1:]
+
+			default:
+				end := strings.IndexFunc(text, func(c rune) bool {
+					return unicode.IsSpace(c) || strings.ContainsRune("{},\"", c)
+				})
+				if end < 0 {
+					end = len(text)
+				}
+				tokens = append(tokens, gcToken{text[:end], false, line})
+				text = text[end:]
+			}
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if quoted != nil {
+		return nil, GcPrtError{quoteLine, "unterminated string"}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,80 @@
+package codon
+
+import (
+	"strings"
+	"testing"
+)
+
+const gcPrtSample = `--**************************************************************************
+--  This is the NCBI genetic code table
+--**************************************************************************
+Genetic-code-table ::= {
+ {
+  name "Standard" ,
+  name "SGC0" ,
+  id 1 ,
+  ncbieaa  "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAAD
+DEEGGGG",
+  sncbieaa "---M------**--*----M---------------M----------------------------"
+  -- Base1  UUUUUUUUUUUUUUUUCCCCCCCCCCCCCCCCAAAAAAAAAAAAAAAAGGGGGGGGGGGGGGGG
+ },
+ {
+  name "Vertebrate Mitochondrial" ,
+  name "SGC1" ,
+  id 2 ,
+  ncbieaa  "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSS**VVVVAAAADDEEGGGG",
+  sncbieaa "----------**--------------------MMMM----------**---M------------"
+ }
+}
+`
+
+func Test_ReadGcPrt(t *testing.T) {
+	codes, err := ReadGcPrt(strings.NewReader(gcPrtSample))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(codes) != 2 {
+		t.Fatalf("Expected 2 codes, got %d", len(codes))
+	}
+
+	for i, id := range []int{1, 2} {
+		expected, _ := Code(id)
+		actual := codes[i]
+		if actual.Id != expected.Id || actual.Name != expected.Name {
+			t.Errorf("Expected code %d %q, got %d %q",
+				expected.Id, expected.Name, actual.Id, actual.Name)
+		}
+		for c, aa := range expected.table {
+			if actual.table[c] != aa {
+				t.Errorf("Code %d: expected %s to be %q, got %q",
+					id, string(c[:]), aa, actual.table[c])
+			}
+			if actual.starts[c] != expected.starts[c] {
+				t.Errorf("Code %d: start codon mismatch for %s", id, string(c[:]))
+			}
+		}
+	}
+}
+
+func Test_ReadGcPrtReportsErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		line int
+	}{
+		{"{ {\n name \"x\" ,\n id 1\n } }", 1},
+		{"{ {\n id 1 ,\n ncbieaa \"FF\",\n sncbieaa \"--\"\n } }", 1},
+		{"{ {\n id one ,\n ncbieaa \"FF\" } }", 1},
+		{"{\n {\n name \"unterminated\n", 3},
+		{"}", 1},
+	}
+
+	for _, test := range tests {
+		_, err := ReadGcPrt(strings.NewReader(test.text))
+		e, ok := err.(GcPrtError)
+		if !ok {
+			t.Errorf("Expected GcPrtError for %q, got %v", test.text, err)
+		} else if e.Line != test.line {
+			t.Errorf("Expected error on line %d for %q, got %d", test.line, test.text, e.Line)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package codon
+
+import (
+	"fmt"
+	"sort"
+)
+
+/// GeneticCode maps codons onto amino acids, and records which codons may
+/// act as start codons.
+type GeneticCode struct {
+	Id     int
+	Name   string
+	table  map[codon]rune
+	starts map[codon]bool
+
+	// codons is the inverse of table, mapping each amino acid (or Stop) onto
+	// the codons that encode it, in NCBI order
+	codons map[rune][]string
+}
+
+/// UnknownCodeError is returned when asking for a genetic code that doesn't
+/// exist.
+type UnknownCodeError int
+
+func (self UnknownCodeError) Error() string {
+	return fmt.Sprintf("Unknown genetic code: %d", int(self))
+}
+
+/// ncbiBases is the order in which NCBI lists the bases of a codon
+var ncbiBases = [4]byte{'U', 'C', 'A', 'G'}
+
+/// ambiguityCodes maps each IUPAC ambiguity code onto the RNA bases it
+/// stands for
+var ambiguityCodes = map[byte]string{
+	'N': "UCAG",
+	'R': "AG",
+	'Y': "UC",
+	'K': "UG",
+	'M': "CA",
+	'S': "CG",
+	'W': "UA",
+	'B': "UCG",
+	'D': "UAG",
+	'H': "UCA",
+	'V': "CAG",
+}
+
+/// builtinCodes indexes the NCBI genetic codes by id
+var builtinCodes = map[int]*GeneticCode{}
+
+/// Standard is the standard genetic code, NCBI table 1
+var Standard *GeneticCode
+
+func init() {
+	for _, c := range ncbiCodes {
+		code, err := NewGeneticCode(c.id, c.name, c.aas, c.starts)
+		if err != nil {
+			panic(err)
+		}
+		builtinCodes[c.id] = code
+	}
+	Standard = builtinCodes[1]
+
+	Table = make(map[codon]rune, len(Standard.table))
+	for c, aa := range Standard.table {
+		Table[c] = aa
+	}
+}
+
+/// Code looks up one of the NCBI genetic codes by its id
+func Code(id int) (*GeneticCode, error) {
+	if code, ok := builtinCodes[id]; ok {
+		return code, nil
+	}
+	return nil, UnknownCodeError(id)
+}
+
+/// Codes returns the ids of all the built-in genetic codes, in order
+func Codes() []int {
+	result := make([]int, 0, len(builtinCodes))
+	for id := range builtinCodes {
+		result = append(result, id)
+	}
+	sort.Ints(result)
+	return result
+}
+
+/// NewGeneticCode creates a genetic code from strings of amino acids and start
+/// codon markers, each listing all 64 codons in NCBI order (UUU, UUC, UUA,
+/// UUG, UCU ... GGG). A '*' amino acid marks a stop codon, and an 'M' in
+/// starts marks a start codon.
+func NewGeneticCode(id int, name, aas, starts string) (*GeneticCode, error) {
+	if len(aas) != 64 {
+		return nil, fmt.Errorf("Expected 64 amino acids for code %d, got %d", id, len(aas))
+	}
+	if len(starts) != 64 {
+		return nil, fmt.Errorf("Expected 64 start markers for code %d, got %d", id, len(starts))
+	}
+
+	code := &GeneticCode{
+		Id:     id,
+		Name:   name,
+		table:  make(map[codon]rune, 64),
+		starts: make(map[codon]bool),
+		codons: make(map[rune][]string),
+	}
+
+	for i := 0; i < 64; i++ {
+		c := codon{ncbiBases[i/16], ncbiBases[(i/4)%4], ncbiBases[i%4]}
+		aa := rune(aas[i])
+		if aa == '*' {
+			aa = Stop
+		}
+		code.table[c] = aa
+		code.codons[aa] = append(code.codons[aa], string(c[:]))
+		if starts[i] == 'M' {
+			code.starts[c] = true
+		}
+	}
+
+	return code, nil
+}
+
+/// Lookup translates a single DNA or RNA codon, returning Stop for stop
+/// codons. T and U are interchangeable. Codons containing IUPAC ambiguity
+/// codes translate to the amino acid that every base they could stand for
+/// would give (e.g. GCN is always A), or X if they disagree. Returns false if
+/// the codon isn't valid.
+func (self *GeneticCode) Lookup(triplet string) (rune, bool) {
+	if len(triplet) != 3 {
+		return 0, false
+	}
+	return self.translate(makeCodon(triplet[0], triplet[1], triplet[2]))
+}
+
+/// IsStart checks whether a codon may act as a start codon in this code
+func (self *GeneticCode) IsStart(triplet string) bool {
+	if len(triplet) != 3 {
+		return false
+	}
+	return self.starts[makeCodon(triplet[0], triplet[1], triplet[2])]
+}
+
+/// StartCodons returns the codons that may act as start codons in this code,
+/// in NCBI order.
+func (self *GeneticCode) StartCodons() []string {
+	result := []string{}
+	for i := 0; i < 64; i++ {
+		c := codon{ncbiBases[i/16], ncbiBases[(i/4)%4], ncbiBases[i%4]}
+		if self.starts[c] {
+			result = append(result, string(c[:]))
+		}
+	}
+	return result
+}
+
+/// translate looks up a codon, resolving any ambiguity codes as per Lookup
+func (self *GeneticCode) translate(c codon) (rune, bool) {
+	if aa, ok := self.table[c]; ok {
+		return aa, true
+	}
+
+	result := rune(-1)
+	for _, x := range expandBase(c[0]) {
+		for _, y := range expandBase(c[1]) {
+			for _, z := range expandBase(c[2]) {
+				aa, ok := self.table[codon{byte(x), byte(y), byte(z)}]
+				if !ok {
+					return 0, false
+				}
+				if result >= 0 && aa != result {
+					return 'X', true
+				}
+				result = aa
+			}
+		}
+	}
+
+	// an invalid base expands to nothing
+	if result < 0 {
+		return 0, false
+	}
+	return result, true
+}
+
+/// makeCodon builds a codon from DNA or RNA bases, converting T to U
+func makeCodon(a, b, c byte) codon {
+	result := codon{a, b, c}
+	for i, base := range result {
+		if base == 'T' {
+			result[i] = 'U'
+		}
+	}
+	return result
+}
+
+/// expandBase returns the unambiguous RNA bases a base could stand for
+func expandBase(base byte) string {
+	if bases, ok := ambiguityCodes[base]; ok {
+		return bases
+	}
+	return string(base)
+}
@@ -0,0 +1,111 @@
+package codon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_StandardCodeMatchesTable(t *testing.T) {
+	for c, aa := range Table {
+		actual, ok := Standard.Lookup(string(c[:]))
+		if !ok || actual != aa {
+			t.Errorf("Expected %s to translate to %q, got %q", string(c[:]), aa, actual)
+		}
+	}
+}
+
+func Test_AllNcbiCodesAreAvailable(t *testing.T) {
+	ids := Codes()
+	if len(ids) != 27 {
+		t.Errorf("Expected 27 codes, got %d", len(ids))
+	}
+	for _, id := range ids {
+		code, err := Code(id)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if code.Id != id {
+			t.Errorf("Expected code %d, got %d", id, code.Id)
+		}
+	}
+}
+
+func Test_UnknownCodeIsAnError(t *testing.T) {
+	for _, id := range []int{0, 7, 8, 17, 34} {
+		if _, err := Code(id); err != UnknownCodeError(id) {
+			t.Errorf("Expected UnknownCodeError for %d, got %v", id, err)
+		}
+	}
+}
+
+func Test_VertebrateMitochondrialCode(t *testing.T) {
+	code, _ := Code(2)
+	expected := map[string]rune{
+		"UGA": 'W', "AGA": Stop, "AGG": Stop, "AUA": 'M', "UAA": Stop,
+	}
+	for c, aa := range expected {
+		if actual, _ := code.Lookup(c); actual != aa {
+			t.Errorf("Expected %s to translate to %q, got %q", c, aa, actual)
+		}
+	}
+}
+
+func Test_StartCodons(t *testing.T) {
+	expected := []string{"UUG", "CUG", "AUG"}
+	if actual := Standard.StartCodons(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+
+	code, _ := Code(11)
+	if !code.IsStart("GUG") || code.IsStart("UGA") {
+		t.Errorf("Unexpected start codons for code 11: %v", code.StartCodons())
+	}
+}
+
+func Test_TranslateWithAlternativeCode(t *testing.T) {
+	code, _ := Code(2)
+	p, err := Translate("AUGUGAAGA", Options{Code: code})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "MW" {
+		t.Errorf("Expected \"MW\", got \"%s\"", p)
+	}
+}
+
+func Test_AlternativeStartTranslatesAsMethionine(t *testing.T) {
+	p, err := Translate("UUGUUG", Options{RequireStart: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "ML" {
+		t.Errorf("Expected \"ML\", got \"%s\"", p)
+	}
+
+	_, err = Translate("GCC", Options{RequireStart: true})
+	if err != (MissingStartError{"GCC", 0}) {
+		t.Errorf("Expected MissingStartError, got %v", err)
+	}
+}
+
+func Test_NewGeneticCodeChecksLengths(t *testing.T) {
+	if _, err := NewGeneticCode(99, "short", "FF", "--"); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func Test_LookupResolvesAmbiguity(t *testing.T) {
+	tests := map[string]rune{
+		"GCN": 'A', "GCT": 'A', "TTY": 'F', "TTN": 'X', "YTR": 'L', "TAR": Stop, "TRA": Stop,
+	}
+	for c, expected := range tests {
+		actual, ok := Standard.Lookup(c)
+		if !ok || actual != expected {
+			t.Errorf("Expected %s to translate to %q, got %q", c, expected, actual)
+		}
+	}
+
+	if _, ok := Standard.Lookup("GCX"); ok {
+		t.Error("Expected GCX to be invalid")
+	}
+}
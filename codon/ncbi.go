@@ -0,0 +1,176 @@
+package codon
+
+/// ncbiCodes holds the NCBI genetic codes, transcribed from the NCBI gc.prt
+/// file. Amino acids and start codons are listed in the usual NCBI codon
+/// order, i.e. UUU, UUC, UUA, UUG, UCU ... GGG. A "*" in the amino acids
+/// marks a stop codon, and an "M" in the starts marks a start codon. Ids 7,
+/// 8 and 17-20 are unassigned.
+var ncbiCodes = []struct {
+	id     int
+	name   string
+	aas    string
+	starts string
+}{
+	{
+		id:     1,
+		name:   "Standard",
+		aas:    "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "---M---------------M---------------M----------------------------",
+	},
+	{
+		id:     2,
+		name:   "Vertebrate Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSS**VVVVAAAADDEEGGGG",
+		starts: "--------------------------------MMMM---------------M------------",
+	},
+	{
+		id:     3,
+		name:   "Yeast Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWTTTTPPPPHHQQRRRRIIMMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "----------------------------------MM----------------------------",
+	},
+	{
+		id:     4,
+		name:   "Mold, Protozoan, and Coelenterate Mitochondrial and Mycoplasma/Spiroplasma",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "--MM---------------M------------MMMM---------------M------------",
+	},
+	{
+		id:     5,
+		name:   "Invertebrate Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSSSVVVVAAAADDEEGGGG",
+		starts: "---M----------------------------MMMM---------------M------------",
+	},
+	{
+		id:     6,
+		name:   "Ciliate, Dasycladacean and Hexamita Nuclear",
+		aas:    "FFLLSSSSYYQQCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M----------------------------",
+	},
+	{
+		id:     9,
+		name:   "Echinoderm and Flatworm Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M---------------M------------",
+	},
+	{
+		id:     10,
+		name:   "Euplotid Nuclear",
+		aas:    "FFLLSSSSYY**CCCWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M----------------------------",
+	},
+	{
+		id:     11,
+		name:   "Bacterial, Archaeal and Plant Plastid",
+		aas:    "FFLLSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "---M---------------M------------MMMM---------------M------------",
+	},
+	{
+		id:     12,
+		name:   "Alternative Yeast Nuclear",
+		aas:    "FFLLSSSSYY**CC*WLLLSPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-------------------M---------------M----------------------------",
+	},
+	{
+		id:     13,
+		name:   "Ascidian Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNKKSSGGVVVVAAAADDEEGGGG",
+		starts: "---M------------------------------MM---------------M------------",
+	},
+	{
+		id:     14,
+		name:   "Alternative Flatworm Mitochondrial",
+		aas:    "FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M----------------------------",
+	},
+	{
+		id:     15,
+		name:   "Blepharisma Macronuclear",
+		aas:    "FFLLSSSSYY*QCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M----------------------------",
+	},
+	{
+		id:     16,
+		name:   "Chlorophycean Mitochondrial",
+		aas:    "FFLLSSSSYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M----------------------------",
+	},
+	{
+		id:     21,
+		name:   "Trematode Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIMMTTTTNNNKSSSSVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M---------------M------------",
+	},
+	{
+		id:     22,
+		name:   "Scenedesmus obliquus Mitochondrial",
+		aas:    "FFLLSS*SYY*LCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-----------------------------------M----------------------------",
+	},
+	{
+		id:     23,
+		name:   "Thraustochytrium Mitochondrial",
+		aas:    "FF*LSSSSYY**CC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "--------------------------------M--M---------------M------------",
+	},
+	{
+		id:     24,
+		name:   "Rhabdopleuridae Mitochondrial",
+		aas:    "FFLLSSSSYY**CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG",
+		starts: "---M---------------M---------------M---------------M------------",
+	},
+	{
+		id:     25,
+		name:   "Candidate Division SR1 and Gracilibacteria",
+		aas:    "FFLLSSSSYY**CCGWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "---M-------------------------------M---------------M------------",
+	},
+	{
+		id:     26,
+		name:   "Pachysolen tannophilus Nuclear",
+		aas:    "FFLLSSSSYY**CC*WLLLAPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "-------------------M---------------M----------------------------",
+	},
+	{
+		id:     27,
+		name:   "Karyorelict Nuclear",
+		aas:    "FFLLSSSSYYQQCCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "--------------*--------------------M----------------------------",
+	},
+	{
+		id:     28,
+		name:   "Condylostoma Nuclear",
+		aas:    "FFLLSSSSYYQQCCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "----------**--*--------------------M----------------------------",
+	},
+	{
+		id:     29,
+		name:   "Mesodinium Nuclear",
+		aas:    "FFLLSSSSYYYYCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "--------------*--------------------M----------------------------",
+	},
+	{
+		id:     30,
+		name:   "Peritrich Nuclear",
+		aas:    "FFLLSSSSYYEECC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "--------------*--------------------M----------------------------",
+	},
+	{
+		id:     31,
+		name:   "Blastocrithidia Nuclear",
+		aas:    "FFLLSSSSYYEECCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "----------**-----------------------M----------------------------",
+	},
+	{
+		id:     32,
+		name:   "Balanophoraceae Plastid",
+		aas:    "FFLLSSSSYY*WCC*WLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSRRVVVVAAAADDEEGGGG",
+		starts: "---M---------------M------------MMMM---------------M------------",
+	},
+	{
+		id:     33,
+		name:   "Cephalodiscidae Mitochondrial",
+		aas:    "FFLLSSSSYYY*CCWWLLLLPPPPHHQQRRRRIIIMTTTTNNKKSSSKVVVVAAAADDEEGGGG",
+		starts: "---M-------*-------M---------------M---------------M------------",
+	},
+}
@@ -0,0 +1,175 @@
+package codon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tcsc/rosalind/basestring"
+)
+
+/// Strand identifies which strand of a DNA molecule an ORF lies on
+type Strand int
+
+const (
+	/// Forward is the strand as given
+	Forward Strand = iota
+
+	/// Reverse is the reverse complement of the strand as given
+	Reverse
+)
+
+func (self Strand) String() string {
+	if self == Reverse {
+		return "-"
+	}
+	return "+"
+}
+
+/// ORF describes an open reading frame: a start codon followed by a run of
+/// codons ending in a stop codon.
+type ORF struct {
+	Strand Strand
+
+	/// Frame is the offset (0, 1 or 2) of the first codon, counted from the
+	/// start of the strand the ORF is on
+	Frame int
+
+	/// Start and End are the bounds [Start, End) of the ORF on the forward
+	/// strand, including the stop codon, whichever strand it is on
+	Start int
+	End   int
+
+	/// Peptide is the translated protein, without the stop
+	Peptide string
+}
+
+/// ORFOptions controls how FindORFs searches for open reading frames
+type ORFOptions struct {
+	/// Code is the genetic code to translate with. Defaults to Standard.
+	Code *GeneticCode
+
+	/// MinLength is the shortest peptide (in amino acids) to report
+	MinLength int
+
+	/// AlternativeStarts allows any of the code's start codons to begin an
+	/// ORF, rather than just AUG
+	AlternativeStarts bool
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// FindORFs scans all six reading frames of a DNA (or RNA) string for open
+/// reading frames. Every start codon begins a new ORF, so ORFs nested inside
+/// each other in the same frame are all reported. Frames are reported in
+/// order, forward strand first, and ORFs within a frame by their starting
+/// position. Ambiguous codons are resolved as per GeneticCode.Lookup, but only
+/// unambiguous codons can start an ORF.
+func FindORFs(seq string, opts ORFOptions) ([]ORF, error) {
+	code := opts.Code
+	if code == nil {
+		code = Standard
+	}
+
+	dna := strings.Map(func(c rune) rune {
+		if c == 'U' || c == 'u' {
+			return 'T'
+		}
+		return c
+	}, seq)
+
+	forward, err := basestring.FromString(dna)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []ORF{}
+	n := forward.Length()
+	strands := []string{
+		toRNA(forward.String()),
+		toRNA(forward.ReverseComplement().String()),
+	}
+	for strand, rna := range strands {
+		for frame := 0; frame < 3; frame++ {
+			for _, orf := range findORFsInFrame(rna, frame, code, opts) {
+				orf.Strand = Strand(strand)
+				if orf.Strand == Reverse {
+					orf.Start, orf.End = n-orf.End, n-orf.Start
+				}
+				result = append(result, orf)
+			}
+		}
+	}
+	return result, nil
+}
+
+/// toRNA converts upper or lowercase DNA to uppercase RNA
+func toRNA(dna string) string {
+	return strings.Map(func(c rune) rune {
+		if c == 'T' || c == 't' {
+			return 'U'
+		}
+		return c
+	}, strings.ToUpper(dna))
+}
+
+/// findORFsInFrame finds the ORFs in a single frame of an RNA string, with
+/// coordinates relative to the start of the string.
+func findORFsInFrame(rna string, frame int, code *GeneticCode, opts ORFOptions) []ORF {
+	result := []ORF{}
+	starts := []int{}
+	var peptide strings.Builder
+
+	for i := frame; i+3 <= len(rna); i += 3 {
+		c := codon{rna[i], rna[i+1], rna[i+2]}
+		aa, ok := code.translate(c)
+		if !ok {
+			starts = starts[:0]
+			continue
+		}
+
+		isStart := c == codon{'A', 'U', 'G'} || (opts.AlternativeStarts && code.starts[c])
+		if isStart {
+			if len(starts) == 0 {
+				peptide.Reset()
+			}
+			starts = append(starts, i)
+		}
+
+		if aa == Stop {
+			if len(starts) > 0 {
+				protein := peptide.String()
+				first := starts[0]
+				for _, start := range starts {
+					// each codon contributes exactly one byte to the peptide,
+					// and every start translates as methionine
+					p := "M" + protein[(start-first)/3+1:]
+					if len(p) >= opts.MinLength {
+						result = append(result, ORF{
+							Frame:   frame,
+							Start:   start,
+							End:     i + 3,
+							Peptide: p,
+						})
+					}
+				}
+			}
+			starts = starts[:0]
+			continue
+		}
+
+		if len(starts) > 0 {
+			peptide.WriteRune(aa)
+		}
+	}
+
+	return result
+}
+
+/// String formats the ORF as a tab-separated line of strand, frame, start,
+/// end and peptide.
+func (self ORF) String() string {
+	return fmt.Sprintf("%s\t%d\t%d\t%d\t%s",
+		self.Strand, self.Frame, self.Start, self.End, self.Peptide)
+}
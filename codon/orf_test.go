@@ -0,0 +1,108 @@
+package codon
+
+import (
+	"sort"
+	"testing"
+)
+
+const rosalindOrf = "AGCCATGTAGCTAACTCAGGTTACATGGGGATGACCCCGCGACTTGGATTAGAGTCTCTTTTGGAATAAGCCTGAATGATCCGAGTAGCATCTCAG"
+
+func Test_FindORFsMatchesRosalindSample(t *testing.T) {
+	orfs, err := FindORFs(rosalindOrf, ORFOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	distinct := map[string]bool{}
+	for _, orf := range orfs {
+		distinct[orf.Peptide] = true
+	}
+	actual := []string{}
+	for p := range distinct {
+		actual = append(actual, p)
+	}
+	sort.Strings(actual)
+
+	expected := []string{"M", "MGMTPRLGLESLLE", "MLLGSFRLIPKETLIQVAGSSPCNLS", "MTPRLGLESLLE"}
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, actual)
+		}
+	}
+}
+
+func Test_FindORFsReportsCoordinates(t *testing.T) {
+	// forward: AUG AAA UAG at 1; reverse complement of the whole string is
+	// CCUAUUUCAUC, which has no start codon
+	orfs, err := FindORFs("GATGAAATAGG", ORFOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(orfs) != 1 {
+		t.Fatalf("Expected 1 ORF, got %v", orfs)
+	}
+	expected := ORF{Strand: Forward, Frame: 1, Start: 1, End: 10, Peptide: "MK"}
+	if orfs[0] != expected {
+		t.Errorf("Expected %v, got %v", expected, orfs[0])
+	}
+
+	orfs, _ = FindORFs("CCTATTTCATC", ORFOptions{})
+	expected.Strand = Reverse
+	expected.Start, expected.End = 1, 10
+	if len(orfs) != 1 || orfs[0] != expected {
+		t.Errorf("Expected [%v], got %v", expected, orfs)
+	}
+}
+
+func Test_FindORFsReportsNestedStarts(t *testing.T) {
+	orfs, _ := FindORFs("ATGAAAATGCCCTAA", ORFOptions{})
+	if len(orfs) != 2 || orfs[0].Peptide != "MKMP" || orfs[1].Peptide != "MP" {
+		t.Fatalf("Expected MKMP and MP, got %v", orfs)
+	}
+	if orfs[1].Start != 6 || orfs[1].End != 15 {
+		t.Errorf("Expected nested ORF at [6:15), got [%d:%d)", orfs[1].Start, orfs[1].End)
+	}
+}
+
+func Test_FindORFsHonoursMinLength(t *testing.T) {
+	orfs, _ := FindORFs("ATGAAAATGCCCTAA", ORFOptions{MinLength: 3})
+	if len(orfs) != 1 || orfs[0].Peptide != "MKMP" {
+		t.Errorf("Expected only MKMP, got %v", orfs)
+	}
+}
+
+func Test_FindORFsWithAlternativeStarts(t *testing.T) {
+	orfs, _ := FindORFs("TTGAAATAA", ORFOptions{})
+	if len(orfs) != 0 {
+		t.Errorf("Expected no ORFs, got %v", orfs)
+	}
+
+	orfs, _ = FindORFs("TTGAAATAA", ORFOptions{AlternativeStarts: true})
+	if len(orfs) != 1 || orfs[0].Peptide != "MK" {
+		t.Errorf("Expected MK, got %v", orfs)
+	}
+}
+
+func Test_FindORFsWithAlternativeCode(t *testing.T) {
+	code, _ := Code(2)
+	orfs, _ := FindORFs("ATGTGAAGA", ORFOptions{Code: code})
+	if len(orfs) != 1 || orfs[0].Peptide != "MW" {
+		t.Errorf("Expected MW, got %v", orfs)
+	}
+}
+
+func Test_AmbiguousCodonsAreResolvedInORFs(t *testing.T) {
+	orfs, _ := FindORFs("ATGNNNGCNAAATAR", ORFOptions{})
+	if len(orfs) != 1 || orfs[0].Peptide != "MXAK" {
+		t.Errorf("Expected MXAK, got %v", orfs)
+	}
+}
+
+func Test_FindORFsRejectsInvalidBases(t *testing.T) {
+	if _, err := FindORFs("ATGXTAA", ORFOptions{}); err == nil {
+		t.Error("Expected an error")
+	}
+}
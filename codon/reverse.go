@@ -0,0 +1,156 @@
+package codon
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+)
+
+/// UnknownAminoAcidError is returned when reverse translating a protein
+/// containing something the genetic code has no codon for. Offset is its
+/// index in the protein.
+type UnknownAminoAcidError struct {
+	AminoAcid rune
+	Offset    int
+}
+
+func (self UnknownAminoAcidError) Error() string {
+	return fmt.Sprintf("No codon for %q at offset %d", self.AminoAcid, self.Offset)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Codons returns the codons that encode an amino acid, in NCBI order. Stop
+/// (or '*') returns the stop codons. The result must not be modified.
+func (self *GeneticCode) Codons(aa rune) []string {
+	if aa == '*' {
+		aa = Stop
+	}
+	return self.codons[aa]
+}
+
+/// CountRNAs returns the number of different RNA strings that could encode a
+/// protein, including the stop codon that ends it. A trailing '*' in the
+/// protein is taken as that stop codon; otherwise one is implied. A '*'
+/// anywhere else stands for a stop codon that was read through.
+func (self *GeneticCode) CountRNAs(protein string) (*big.Int, error) {
+	choices, err := self.reverseChoices(protein)
+	if err != nil {
+		return nil, err
+	}
+
+	result := big.NewInt(1)
+	n := new(big.Int)
+	for _, codons := range choices {
+		result.Mul(result, n.SetInt64(int64(len(codons))))
+	}
+	return result, nil
+}
+
+/// CountRNAsMod is CountRNAs modulo m, computed without big integers unless
+/// m is too large for that to be safe. Panics if m isn't positive.
+func (self *GeneticCode) CountRNAsMod(protein string, m int) (int, error) {
+	if m <= 0 {
+		panic(fmt.Sprintf("codon: modulus must be positive, not %d", m))
+	}
+
+	// no amino acid has more than 64 codons, so below this limit the
+	// running product can't overflow
+	if m > math.MaxInt/64 {
+		count, err := self.CountRNAs(protein)
+		if err != nil {
+			return 0, err
+		}
+		return int(count.Mod(count, big.NewInt(int64(m))).Int64()), nil
+	}
+
+	choices, err := self.reverseChoices(protein)
+	if err != nil {
+		return 0, err
+	}
+
+	result := 1 % m
+	for _, codons := range choices {
+		result = (result * len(codons)) % m
+	}
+	return result, nil
+}
+
+/// ReverseTranslate lazily enumerates every RNA string that could encode a
+/// protein, including its stop codon (see CountRNAs). The RNAs are produced
+/// in order, varying the last codon fastest.
+func (self *GeneticCode) ReverseTranslate(protein string) (iter.Seq[string], error) {
+	choices, err := self.reverseChoices(protein)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(string) bool) {
+		indexes := make([]int, len(choices))
+		var rna strings.Builder
+		for {
+			rna.Reset()
+			rna.Grow(3 * len(choices))
+			for i, codons := range choices {
+				rna.WriteString(codons[indexes[i]])
+			}
+			if !yield(rna.String()) {
+				return
+			}
+
+			// advance the rightmost codon that has choices left, resetting
+			// everything after it
+			i := len(indexes) - 1
+			for ; i >= 0; i-- {
+				indexes[i]++
+				if indexes[i] < len(choices[i]) {
+					break
+				}
+				indexes[i] = 0
+			}
+			if i < 0 {
+				return
+			}
+		}
+	}, nil
+}
+
+/// SampleRNA picks one of the RNA strings that could encode a protein (see
+/// CountRNAs) uniformly at random, using the given source of randomness.
+func (self *GeneticCode) SampleRNA(protein string, rng *rand.Rand) (string, error) {
+	choices, err := self.reverseChoices(protein)
+	if err != nil {
+		return "", err
+	}
+
+	// every combination of codons is a distinct RNA, so choosing each codon
+	// uniformly chooses the RNA uniformly
+	var rna strings.Builder
+	rna.Grow(3 * len(choices))
+	for _, codons := range choices {
+		rna.WriteString(codons[rng.Intn(len(codons))])
+	}
+	return rna.String(), nil
+}
+
+/// reverseChoices lists the codons that could encode each residue of a
+/// protein, followed by the stop codons.
+func (self *GeneticCode) reverseChoices(protein string) ([][]string, error) {
+	protein = strings.TrimSuffix(protein, "*")
+
+	residues := []rune(protein)
+	result := make([][]string, 0, len(residues)+1)
+	for i, aa := range residues {
+		codons := self.Codons(aa)
+		if aa == Stop || len(codons) == 0 {
+			return nil, UnknownAminoAcidError{aa, i}
+		}
+		result = append(result, codons)
+	}
+	return append(result, self.codons[Stop]), nil
+}
@@ -0,0 +1,148 @@
+package codon
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_CodonsInvertsTable(t *testing.T) {
+	total := 0
+	for _, aa := range "ACDEFGHIKLMNPQRSTVWY*" {
+		for _, c := range Standard.Codons(aa) {
+			total++
+			actual, _ := Standard.Lookup(c)
+			if actual != aa && !(aa == '*' && actual == Stop) {
+				t.Errorf("Expected %s to encode %q, got %q", c, aa, actual)
+			}
+		}
+	}
+	if total != 64 {
+		t.Errorf("Expected 64 codons, got %d", total)
+	}
+
+	expected := []string{"UAA", "UAG", "UGA"}
+	if actual := Standard.Codons(Stop); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
+
+func Test_CountRNAsIncludesStop(t *testing.T) {
+	n, err := Standard.CountRNAs("MA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if n.Cmp(big.NewInt(12)) != 0 {
+		t.Errorf("Expected 12, got %s", n)
+	}
+
+	n, _ = Standard.CountRNAs("MA*")
+	if n.Cmp(big.NewInt(12)) != 0 {
+		t.Errorf("Expected a trailing stop to count once, got %s", n)
+	}
+
+	n, _ = Standard.CountRNAs("")
+	if n.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("Expected 3, got %s", n)
+	}
+}
+
+func Test_CountRNAsModMatchesBigCount(t *testing.T) {
+	protein := "MLLRSWGSVVVLRRLLSSLLLLLSSLLRRAAAAGGRRSSLLVV"
+	expected, _ := Standard.CountRNAs(protein)
+	for _, m := range []int{1, 7, 1000000, 1 << 31, math.MaxInt/6 + 1, math.MaxInt} {
+		actual, err := Standard.CountRNAsMod(protein, m)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		want := new(big.Int).Mod(expected, big.NewInt(int64(m)))
+		if int64(actual) != want.Int64() {
+			t.Errorf("Expected %s mod %d, got %d", want, m, actual)
+		}
+	}
+}
+
+func Test_CountRNAsRejectsUnknownAminoAcids(t *testing.T) {
+	_, err := Standard.CountRNAs("MAB")
+	if err != (UnknownAminoAcidError{'B', 2}) {
+		t.Errorf("Expected UnknownAminoAcidError, got %v", err)
+	}
+
+	// the offset counts residues, not bytes
+	_, err = Standard.CountRNAs("MÅB")
+	if err != (UnknownAminoAcidError{'Å', 1}) {
+		t.Errorf("Expected UnknownAminoAcidError at 1, got %v", err)
+	}
+}
+
+func Test_ReverseTranslateEnumeratesEveryRNA(t *testing.T) {
+	rnas, err := Standard.ReverseTranslate("MW")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	actual := []string{}
+	for rna := range rnas {
+		actual = append(actual, rna)
+	}
+	expected := []string{"AUGUGGUAA", "AUGUGGUAG", "AUGUGGUGA"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
+
+func Test_ReverseTranslateRoundTrips(t *testing.T) {
+	rnas, _ := Standard.ReverseTranslate("MAK")
+	count := 0
+	for rna := range rnas {
+		count++
+		p, err := Translate(rna, Options{})
+		if err != nil || p != "MAK" {
+			t.Errorf("Expected %s to translate to MAK, got %q (%v)", rna, p, err)
+		}
+	}
+	if count != 24 {
+		t.Errorf("Expected 24 RNAs, got %d", count)
+	}
+}
+
+func Test_ReverseTranslateIsLazy(t *testing.T) {
+	rnas, _ := Standard.ReverseTranslate("LLLLLLLLLLLLLLLLLLLL")
+	for rna := range rnas {
+		if rna != "UUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUUAUAA" {
+			t.Errorf("Unexpected first RNA %s", rna)
+		}
+		break
+	}
+}
+
+func Test_SampleRNAPicksEveryRNAUniformly(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	const samples = 24000
+	for i := 0; i < samples; i++ {
+		rna, err := Standard.SampleRNA("MAK", rng)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		counts[rna]++
+	}
+
+	if len(counts) != 24 {
+		t.Errorf("Expected all 24 RNAs to be sampled, got %d", len(counts))
+	}
+	for rna, n := range counts {
+		if p, _ := Translate(rna, Options{}); p != "MAK" {
+			t.Errorf("Expected %s to translate to MAK, got %q", rna, p)
+		}
+		if n < 800 || n > 1200 {
+			t.Errorf("Expected about 1000 samples of %s, got %d", rna, n)
+		}
+	}
+
+	if _, err := Standard.SampleRNA("MJ", rng); err == nil {
+		t.Error("Expected an error for an unknown amino acid")
+	}
+}
@@ -0,0 +1,56 @@
+package codon
+
+import (
+	"sort"
+
+	"github.com/tcsc/rosalind/gst"
+)
+
+/// span is a half-open range [start, end) of a gene
+type span struct {
+	start int
+	end   int
+}
+
+/// Splice removes every occurrence of each intron from a gene, returning the
+/// remaining exons joined together. Occurrences are all located in the
+/// original gene, so overlapping introns are removed as one, and removing an
+/// intron never creates a new occurrence to remove.
+func Splice(gene string, introns ...string) string {
+	tree := gst.New(gene)
+
+	removed := []span{}
+	for _, intron := range introns {
+		if len(intron) == 0 {
+			continue
+		}
+		for _, loc := range tree.FindAll(intron) {
+			removed = append(removed, span{loc.Offset, loc.Offset + len(intron)})
+		}
+	}
+	if len(removed) == 0 {
+		return gene
+	}
+
+	// FindAll doesn't order its results, so sort them to walk the gene from
+	// start to finish
+	sort.Slice(removed, func(i, j int) bool {
+		return removed[i].start < removed[j].start
+	})
+
+	result := make([]byte, 0, len(gene))
+	i := 0
+	for _, intron := range removed {
+		if intron.start > i {
+			result = append(result, gene[i:intron.start]...)
+		}
+		i = max(i, intron.end)
+	}
+	return string(append(result, gene[i:]...))
+}
+
+/// SpliceAndTranslate splices the introns out of a gene and translates the
+/// resulting coding sequence into protein.
+func SpliceAndTranslate(gene string, introns []string, opts Options) (string, error) {
+	return Translate(Splice(gene, introns...), opts)
+}
@@ -0,0 +1,37 @@
+package codon
+
+import (
+	"testing"
+)
+
+func Test_SpliceMatchesRosalindSample(t *testing.T) {
+	gene := "ATGGTCTACATAGCTGACAAACAGCACGTAGCAATCGGTCGAATCTCGAGAGGCATATGGTCACATGATCGGTCGAGCGTGTTTCAAAGTTTGCGCCTAG"
+	p, err := SpliceAndTranslate(gene, []string{"ATCGGTCGAA", "ATCGGTCGAGCGTGT"}, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "MVYIADKQHVASREAYGHMFKVCA" {
+		t.Errorf("Expected \"MVYIADKQHVASREAYGHMFKVCA\", got \"%s\"", p)
+	}
+}
+
+func Test_SpliceRemovesEveryOccurrence(t *testing.T) {
+	tests := []struct {
+		gene     string
+		introns  []string
+		expected string
+	}{
+		{"AAGGTTGGCC", []string{"GG"}, "AATTCC"},
+		{"AAGGGCC", []string{"GG"}, "AACC"},
+		{"AAGGTTCC", []string{"GGT", "GTT"}, "AACC"},
+		{"AACC", []string{"GG", ""}, "AACC"},
+		{"AACC", nil, "AACC"},
+		{"GGAACCGG", []string{"GG"}, "AACC"},
+	}
+	for _, test := range tests {
+		if actual := Splice(test.gene, test.introns...); actual != test.expected {
+			t.Errorf("Expected %s spliced by %v to be %s, got %s",
+				test.gene, test.introns, test.expected, actual)
+		}
+	}
+}
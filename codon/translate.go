@@ -0,0 +1,158 @@
+package codon
+
+import (
+	"fmt"
+	"strings"
+)
+
+/// Stop is the value Table holds for stop codons
+const Stop = '\x00'
+
+/// StopPolicy defines what Translate does when it reaches a stop codon
+type StopPolicy int
+
+const (
+	/// StopTruncate ends the translation at the first stop codon
+	StopTruncate StopPolicy = iota
+
+	/// StopEmit writes a '*' for each stop codon and carries on translating
+	StopEmit
+
+	/// StopError fails the translation with a StopCodonError
+	StopError
+)
+
+/// Options controls how Translate converts RNA into protein
+type Options struct {
+	/// Code is the genetic code to translate with. Defaults to Standard.
+	Code *GeneticCode
+
+	/// Frame is the offset (0, 1 or 2) of the first base of the first codon
+	Frame int
+
+	/// Stop defines what happens when a stop codon is reached
+	Stop StopPolicy
+
+	/// RequireStart makes it an error for the first codon not to be a start
+	/// codon. The start codon is always translated as 'M', even if it is an
+	/// alternative start codon that would otherwise encode something else.
+	RequireStart bool
+
+	/// AllowPartial silently drops any bases left over after the last whole
+	/// codon, rather than failing with a PartialCodonError
+	AllowPartial bool
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// InvalidCodonError is returned when translating a triplet that isn't a
+/// valid codon. Offset is the index of its first base in the sequence.
+type InvalidCodonError struct {
+	Codon  string
+	Offset int
+}
+
+func (self InvalidCodonError) Error() string {
+	return fmt.Sprintf("Invalid codon %q at offset %d", self.Codon, self.Offset)
+}
+
+/// StopCodonError is returned when a stop codon is reached under the
+/// StopError policy.
+type StopCodonError struct {
+	Codon  string
+	Offset int
+}
+
+func (self StopCodonError) Error() string {
+	return fmt.Sprintf("Stop codon %s at offset %d", self.Codon, self.Offset)
+}
+
+/// MissingStartError is returned when a start codon is required but the
+/// translation begins with something else.
+type MissingStartError struct {
+	Codon  string
+	Offset int
+}
+
+func (self MissingStartError) Error() string {
+	return fmt.Sprintf("Expected start codon at offset %d, got %q", self.Offset, self.Codon)
+}
+
+/// PartialCodonError is returned when the sequence doesn't end on a codon
+/// boundary.
+type PartialCodonError struct {
+	Bases  string
+	Offset int
+}
+
+func (self PartialCodonError) Error() string {
+	return fmt.Sprintf("Partial codon %q at offset %d", self.Bases, self.Offset)
+}
+
+/// InvalidFrameError is returned when asked to translate in a frame other
+/// than 0, 1 or 2.
+type InvalidFrameError int
+
+func (self InvalidFrameError) Error() string {
+	return fmt.Sprintf("Invalid reading frame: %d", int(self))
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Translate converts a string of RNA or DNA bases into a protein string, one
+/// codon at a time, as directed by opts. Ambiguous codons are resolved as per
+/// GeneticCode.Lookup.
+func Translate(seq string, opts Options) (string, error) {
+	if opts.Frame < 0 || opts.Frame > 2 {
+		return "", InvalidFrameError(opts.Frame)
+	}
+
+	code := opts.Code
+	if code == nil {
+		code = Standard
+	}
+
+	var result strings.Builder
+	result.Grow(len(seq) / 3)
+
+	i := opts.Frame
+	for ; i+3 <= len(seq); i += 3 {
+		c := makeCodon(seq[i], seq[i+1], seq[i+2])
+		aa, ok := code.translate(c)
+		if !ok {
+			return "", InvalidCodonError{seq[i : i+3], i}
+		}
+
+		if opts.RequireStart && i == opts.Frame {
+			if !code.starts[c] {
+				return "", MissingStartError{seq[i : i+3], i}
+			}
+			aa = 'M'
+		}
+
+		if aa == Stop {
+			switch opts.Stop {
+			case StopTruncate:
+				return result.String(), nil
+			case StopError:
+				return "", StopCodonError{seq[i : i+3], i}
+			}
+			aa = '*'
+		}
+		result.WriteRune(aa)
+	}
+
+	if opts.RequireStart && i == opts.Frame {
+		return "", MissingStartError{seq[min(i, len(seq)):], i}
+	}
+
+	if i < len(seq) && !opts.AllowPartial {
+		return "", PartialCodonError{seq[i:], i}
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,112 @@
+package codon
+
+import (
+	"testing"
+)
+
+const rosalindProt = "AUGGCCAUGGCGCCCAGAACUGAGAUCAAUAGUACCCGUAUUAACGGGUGA"
+
+func Test_TranslateStopsAtStopCodon(t *testing.T) {
+	p, err := Translate(rosalindProt, Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "MAMAPRTEINSTRING" {
+		t.Errorf("Expected \"MAMAPRTEINSTRING\", got \"%s\"", p)
+	}
+}
+
+func Test_TranslateCanEmitStops(t *testing.T) {
+	p, err := Translate("AUGUAAGCCUGA", Options{Stop: StopEmit})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "M*A*" {
+		t.Errorf("Expected \"M*A*\", got \"%s\"", p)
+	}
+}
+
+func Test_TranslateCanFailOnStops(t *testing.T) {
+	_, err := Translate("AUGUAAGCC", Options{Stop: StopError})
+	if err != (StopCodonError{"UAA", 3}) {
+		t.Errorf("Expected StopCodonError, got %v", err)
+	}
+}
+
+func Test_TranslateHonoursFrame(t *testing.T) {
+	p, err := Translate("GAUGGCCUAA", Options{Frame: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "MA" {
+		t.Errorf("Expected \"MA\", got \"%s\"", p)
+	}
+
+	if _, err := Translate("AUG", Options{Frame: 3}); err != InvalidFrameError(3) {
+		t.Errorf("Expected InvalidFrameError, got %v", err)
+	}
+}
+
+func Test_TranslateCanRequireStart(t *testing.T) {
+	if _, err := Translate("GCCAUG", Options{RequireStart: true}); err != (MissingStartError{"GCC", 0}) {
+		t.Errorf("Expected MissingStartError, got %v", err)
+	}
+	if _, err := Translate("", Options{RequireStart: true}); err == nil {
+		t.Error("Expected MissingStartError for empty sequence")
+	}
+	if _, err := Translate("A", Options{Frame: 2, RequireStart: true}); err == nil {
+		t.Error("Expected MissingStartError for short sequence")
+	}
+	if p, _ := Translate("AUGGCC", Options{RequireStart: true}); p != "MA" {
+		t.Errorf("Expected \"MA\", got \"%s\"", p)
+	}
+}
+
+func Test_TranslateRejectsInvalidCodons(t *testing.T) {
+	if _, err := Translate("AUGGXCAUG", Options{}); err != (InvalidCodonError{"GXC", 3}) {
+		t.Errorf("Expected InvalidCodonError, got %v", err)
+	}
+}
+
+func Test_TranslateRejectsPartialCodons(t *testing.T) {
+	if _, err := Translate("AUGGC", Options{}); err != (PartialCodonError{"GC", 3}) {
+		t.Errorf("Expected PartialCodonError, got %v", err)
+	}
+
+	p, err := Translate("AUGGC", Options{AllowPartial: true})
+	if err != nil || p != "M" {
+		t.Errorf("Expected \"M\", got \"%s\" (%v)", p, err)
+	}
+}
+
+func Test_TranslateAcceptsDNA(t *testing.T) {
+	p, err := Translate("ATGGCCTGGTAA", Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "MAW" {
+		t.Errorf("Expected \"MAW\", got \"%s\"", p)
+	}
+}
+
+func Test_TranslateResolvesAmbiguousCodons(t *testing.T) {
+	p, err := Translate("GCNGGRAARNNNTAY", Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "AGKXY" {
+		t.Errorf("Expected \"AGKXY\", got \"%s\"", p)
+	}
+
+	p, _ = Translate("AUGTRA", Options{})
+	if p != "M" {
+		t.Errorf("Expected TRA to be a stop, got \"%s\"", p)
+	}
+}
+
+func Test_TranslateRejectsInvalidBases(t *testing.T) {
+	_, err := Translate("AUGGCZ", Options{})
+	if err != (InvalidCodonError{"GCZ", 3}) {
+		t.Errorf("Expected InvalidCodonError, got %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package codon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/// Usage counts how often each codon appears in a set of coding sequences,
+/// under a given genetic code. Codons are grouped into synonymous families by
+/// the amino acid they encode, with the stop codons forming a family of their
+/// own.
+type Usage struct {
+	code   *GeneticCode
+	counts map[codon]int
+	total  int
+}
+
+/// UsageError describes a problem computing codon usage statistics
+type UsageError string
+
+func (self UsageError) Error() string {
+	return fmt.Sprintf("Codon usage: %s", string(self))
+}
+
+/// UsageFormatError describes a problem parsing a codon usage table
+type UsageFormatError struct {
+	Line int
+	Msg  string
+}
+
+func (self UsageFormatError) Error() string {
+	return fmt.Sprintf("Codon usage table line %d: %s", self.Line, self.Msg)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// NewUsage creates an empty usage table. A nil code means Standard.
+func NewUsage(code *GeneticCode) *Usage {
+	if code == nil {
+		code = Standard
+	}
+	return &Usage{
+		code:   code,
+		counts: make(map[codon]int, 64),
+	}
+}
+
+/// Code returns the genetic code the table groups codons by
+func (self *Usage) Code() *GeneticCode {
+	return self.code
+}
+
+/// Add counts the codons in a coding sequence of DNA or RNA, read in frame 0.
+/// Ambiguous codons aren't counted. Fails without counting anything if the
+/// sequence contains an invalid codon or doesn't end on a codon boundary.
+func (self *Usage) Add(seq string) error {
+	codons, err := self.codonsOf(seq)
+	if err != nil {
+		return err
+	}
+	for _, c := range codons {
+		self.counts[c]++
+		self.total++
+	}
+	return nil
+}
+
+/// Merge adds the counts from another table, e.g. to build a genome-wide
+/// table from per-gene ones. Both tables must use the same genetic code.
+func (self *Usage) Merge(other *Usage) error {
+	if other.code != self.code {
+		return UsageError(fmt.Sprintf("can't merge code %d into code %d",
+			other.code.Id, self.code.Id))
+	}
+	for c, n := range other.counts {
+		self.counts[c] += n
+	}
+	self.total += other.total
+	return nil
+}
+
+/// Count returns the number of times a codon has been seen
+func (self *Usage) Count(triplet string) int {
+	if len(triplet) != 3 {
+		return 0
+	}
+	return self.counts[makeCodon(triplet[0], triplet[1], triplet[2])]
+}
+
+/// Total returns the number of codons counted
+func (self *Usage) Total() int {
+	return self.total
+}
+
+/// Frequency returns the fraction of all counted codons that are this codon
+func (self *Usage) Frequency(triplet string) float64 {
+	if self.total == 0 {
+		return 0
+	}
+	return float64(self.Count(triplet)) / float64(self.total)
+}
+
+/// RSCU returns the relative synonymous codon usage of a codon: how often it
+/// was seen relative to the mean of its synonymous family. A value of 1 means
+/// no bias. Returns 0 if nothing in the family has been seen.
+func (self *Usage) RSCU(triplet string) float64 {
+	family := self.family(triplet)
+	sum := 0
+	for _, c := range family {
+		sum += self.Count(c)
+	}
+	if sum == 0 {
+		return 0
+	}
+	return float64(self.Count(triplet)) * float64(len(family)) / float64(sum)
+}
+
+/// Weight returns the relative adaptiveness of a codon: its count relative
+/// to the most used codon in its synonymous family. Codons that were never
+/// seen get a weight of 0.5 relative to the most used (following Sharp & Li),
+/// so that they don't force the CAI of any gene using them to zero. Returns 0
+/// if nothing in the family has been seen.
+func (self *Usage) Weight(triplet string) float64 {
+	best := 0
+	for _, c := range self.family(triplet) {
+		best = max(best, self.Count(c))
+	}
+	if best == 0 {
+		return 0
+	}
+	return max(float64(self.Count(triplet)), 0.5) / float64(best)
+}
+
+/// CAI computes the Codon Adaptation Index of a coding sequence against this
+/// table as a reference: the geometric mean of the weights of its codons.
+/// Codons with no synonyms (e.g. AUG in the standard code), stop codons and
+/// ambiguous codons are left out.
+func (self *Usage) CAI(seq string) (float64, error) {
+	codons, err := self.codonsOf(seq)
+	if err != nil {
+		return 0, err
+	}
+
+	sum := 0.0
+	n := 0
+	for _, c := range codons {
+		triplet := string(c[:])
+		family := self.family(triplet)
+		if len(family) < 2 || self.code.table[c] == Stop {
+			continue
+		}
+
+		w := self.Weight(triplet)
+		if w == 0 {
+			return 0, UsageError(fmt.Sprintf("no reference usage for codon %s", triplet))
+		}
+		sum += math.Log(w)
+		n++
+	}
+
+	if n == 0 {
+		return 0, UsageError("no informative codons")
+	}
+	return math.Exp(sum / float64(n)), nil
+}
+
+/// WriteTo writes the table out as tab-separated text, with a header line
+/// and then one line per codon in NCBI order, giving the codon, its amino
+/// acid ('*' for stop), count, frequency and RSCU.
+func (self *Usage) WriteTo(w io.Writer) (int64, error) {
+	out := bufio.NewWriter(w)
+	total := 0
+
+	n, _ := fmt.Fprintf(out, "codon\tamino_acid\tcount\tfrequency\trscu\n")
+	total += n
+	for i := 0; i < 64; i++ {
+		c := codon{ncbiBases[i/16], ncbiBases[(i/4)%4], ncbiBases[i%4]}
+		triplet := string(c[:])
+		aa := self.code.table[c]
+		if aa == Stop {
+			aa = '*'
+		}
+		n, _ := fmt.Fprintf(out, "%s\t%c\t%d\t%.6f\t%.4f\n",
+			triplet, aa, self.Count(triplet), self.Frequency(triplet), self.RSCU(triplet))
+		total += n
+	}
+
+	return int64(total), out.Flush()
+}
+
+/// ReadUsage reads a usage table as written by WriteTo. Only the codon and
+/// count columns are used, and may appear in any order; the header line names
+/// them. Blank lines and lines starting with '#' are ignored. Codons may be
+/// given as DNA or RNA, and any missing from the table are taken as unseen.
+/// A nil code means Standard.
+func ReadUsage(r io.Reader, code *GeneticCode) (*Usage, error) {
+	result := NewUsage(code)
+	codonColumn, countColumn := -1, -1
+
+	s := bufio.NewScanner(r)
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+
+		if codonColumn < 0 {
+			for i, name := range fields {
+				switch strings.TrimSpace(name) {
+				case "codon":
+					codonColumn = i
+				case "count":
+					countColumn = i
+				}
+			}
+			if codonColumn < 0 || countColumn < 0 {
+				return nil, UsageFormatError{line, "header must name codon and count columns"}
+			}
+			continue
+		}
+
+		if len(fields) <= max(codonColumn, countColumn) {
+			return nil, UsageFormatError{line, "missing columns"}
+		}
+
+		triplet := strings.ToUpper(strings.TrimSpace(fields[codonColumn]))
+		if len(triplet) != 3 {
+			return nil, UsageFormatError{line, fmt.Sprintf("bad codon %q", triplet)}
+		}
+		c := makeCodon(triplet[0], triplet[1], triplet[2])
+		if _, ok := result.code.table[c]; !ok {
+			return nil, UsageFormatError{line, fmt.Sprintf("bad codon %q", triplet)}
+		}
+
+		count, err := strconv.Atoi(strings.TrimSpace(fields[countColumn]))
+		if err != nil || count < 0 {
+			return nil, UsageFormatError{line, fmt.Sprintf("bad count %q", fields[countColumn])}
+		}
+
+		result.total += count - result.counts[c]
+		result.counts[c] = count
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if codonColumn < 0 {
+		return nil, UsageFormatError{line, "missing header"}
+	}
+	return result, nil
+}
+
+/// family returns the codons synonymous with a codon, including itself
+func (self *Usage) family(triplet string) []string {
+	if len(triplet) != 3 {
+		return nil
+	}
+	aa, ok := self.code.table[makeCodon(triplet[0], triplet[1], triplet[2])]
+	if !ok {
+		return nil
+	}
+	return self.code.codons[aa]
+}
+
+/// codonsOf splits a coding sequence into its unambiguous codons
+func (self *Usage) codonsOf(seq string) ([]codon, error) {
+	seq = strings.ToUpper(seq)
+	result := make([]codon, 0, len(seq)/3)
+
+	i := 0
+	for ; i+3 <= len(seq); i += 3 {
+		c := makeCodon(seq[i], seq[i+1], seq[i+2])
+		if _, ok := self.code.table[c]; ok {
+			result = append(result, c)
+			continue
+		}
+		if _, ok := self.code.translate(c); !ok {
+			return nil, InvalidCodonError{seq[i : i+3], i}
+		}
+	}
+
+	if i < len(seq) {
+		return nil, PartialCodonError{seq[i:], i}
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package codon
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func Test_UsageCountsCodons(t *testing.T) {
+	u := NewUsage(nil)
+	if err := u.Add("ATGGCTGCTGCCTAA"); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if err := u.Add("AUGGCNGCGUGA"); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	if u.Total() != 8 {
+		t.Errorf("Expected 8 codons, got %d", u.Total())
+	}
+	expected := map[string]int{"AUG": 2, "GCU": 2, "GCT": 2, "GCC": 1, "GCG": 1, "GCA": 0, "UAA": 1}
+	for c, n := range expected {
+		if u.Count(c) != n {
+			t.Errorf("Expected %d %s, got %d", n, c, u.Count(c))
+		}
+	}
+	if !approxEqual(u.Frequency("AUG"), 0.25) {
+		t.Errorf("Expected frequency 0.25, got %f", u.Frequency("AUG"))
+	}
+}
+
+func Test_UsageAddIsAtomic(t *testing.T) {
+	u := NewUsage(nil)
+	if err := u.Add("ATGGCTGCTGC"); err != (PartialCodonError{"GC", 9}) {
+		t.Errorf("Expected PartialCodonError, got %v", err)
+	}
+	if err := u.Add("ATGGXT"); err != (InvalidCodonError{"GXT", 3}) {
+		t.Errorf("Expected InvalidCodonError, got %v", err)
+	}
+	if u.Total() != 0 {
+		t.Errorf("Expected nothing to be counted, got %d", u.Total())
+	}
+}
+
+func Test_RSCU(t *testing.T) {
+	u := NewUsage(nil)
+	u.Add("GCUGCUGCUGCC")
+
+	expected := map[string]float64{"GCU": 3, "GCC": 1, "GCA": 0, "GCG": 0, "AUG": 0}
+	for c, rscu := range expected {
+		if !approxEqual(u.RSCU(c), rscu) {
+			t.Errorf("Expected RSCU %f for %s, got %f", rscu, c, u.RSCU(c))
+		}
+	}
+}
+
+func Test_CAI(t *testing.T) {
+	ref := NewUsage(nil)
+	ref.Add("GCUGCUGCUGCUGCCGCCAAAAAAAAG")
+
+	// methionine and the stop codon are ignored
+	cai, err := ref.CAI("AUGGCUAAAUAA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if !approxEqual(cai, 1) {
+		t.Errorf("Expected CAI 1, got %f", cai)
+	}
+
+	cai, _ = ref.CAI("GCCAAG")
+	if !approxEqual(cai, 0.5) {
+		t.Errorf("Expected CAI 0.5, got %f", cai)
+	}
+
+	// unseen codons count as half a use
+	cai, _ = ref.CAI("GCG")
+	if !approxEqual(cai, 0.125) {
+		t.Errorf("Expected CAI 0.125, got %f", cai)
+	}
+
+	if _, err := ref.CAI("AUGUGG"); err == nil {
+		t.Error("Expected an error for a gene with no informative codons")
+	}
+	if _, err := ref.CAI("UUU"); err == nil {
+		t.Error("Expected an error for a codon with no reference usage")
+	}
+}
+
+func Test_UsageMerge(t *testing.T) {
+	a := NewUsage(nil)
+	a.Add("GCUGCU")
+	b := NewUsage(nil)
+	b.Add("GCUAAA")
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if a.Total() != 4 || a.Count("GCU") != 3 || a.Count("AAA") != 1 {
+		t.Errorf("Unexpected merged counts: %d %d %d", a.Total(), a.Count("GCU"), a.Count("AAA"))
+	}
+
+	mito, _ := Code(2)
+	if err := a.Merge(NewUsage(mito)); err == nil {
+		t.Error("Expected an error merging different codes")
+	}
+}
+
+func Test_UsageTableRoundTrips(t *testing.T) {
+	u := NewUsage(nil)
+	u.Add("AUGGCUGCUGCCUUUUAA")
+
+	var buf bytes.Buffer
+	if _, err := u.WriteTo(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 65 {
+		t.Fatalf("Expected 65 lines, got %d", len(lines))
+	}
+	if lines[1] != "UUU\tF\t1\t0.166667\t2.0000" {
+		t.Errorf("Unexpected line %q", lines[1])
+	}
+
+	v, err := ReadUsage(&buf, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if v.Total() != u.Total() {
+		t.Errorf("Expected %d codons, got %d", u.Total(), v.Total())
+	}
+	for c := range u.counts {
+		if v.counts[c] != u.counts[c] {
+			t.Errorf("Expected %d %s, got %d", u.counts[c], string(c[:]), v.counts[c])
+		}
+	}
+}
+
+func Test_ReadUsageAcceptsOtherLayouts(t *testing.T) {
+	text := "# a comment\ncount\tcodon\n\n5\tGCT\n3\tgcc\n"
+	u, err := ReadUsage(strings.NewReader(text), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if u.Total() != 8 || u.Count("GCU") != 5 || u.Count("GCC") != 3 {
+		t.Errorf("Unexpected counts: %d %d %d", u.Total(), u.Count("GCU"), u.Count("GCC"))
+	}
+}
+
+func Test_ReadUsageReportsErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		line int
+	}{
+		{"codon\tnumber\n", 1},
+		{"codon\tcount\nGCU\n", 2},
+		{"codon\tcount\nGCU\t1\nGXU\t2\n", 3},
+		{"codon\tcount\nGCU\tmany\n", 2},
+		{"\n", 1},
+	}
+	for _, test := range tests {
+		_, err := ReadUsage(strings.NewReader(test.text), nil)
+		e, ok := err.(UsageFormatError)
+		if !ok {
+			t.Errorf("Expected UsageFormatError for %q, got %v", test.text, err)
+		} else if e.Line != test.line {
+			t.Errorf("Expected error on line %d for %q, got %d", test.line, test.text, e.Line)
+		}
+	}
+}
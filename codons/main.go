@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/tcsc/rosalind/codon"
+	"github.com/tcsc/rosalind/fasta"
+	"io"
+	"os"
+)
+
+type args struct {
+	filename  string
+	code      int
+	reference string
+	perGene   bool
+}
+
+func parseArgs() args {
+	result := args{}
+	flag.IntVar(&result.code, "code", 1, "NCBI genetic code to use")
+	flag.StringVar(&result.reference, "ref", "",
+		"Reference usage table; prints the CAI of each gene against it")
+	flag.BoolVar(&result.perGene, "per-gene", false,
+		"Print a usage table for each gene, rather than one for them all")
+	flag.Parse()
+
+	result.filename = flag.Arg(0)
+	return result
+}
+
+func readReference(filename string, code *codon.GeneticCode) *codon.Usage {
+	file, err := os.Open(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+
+	usage, err := codon.ReadUsage(file, code)
+	if err != nil {
+		panic(err)
+	}
+	return usage
+}
+
+func main() {
+	args := parseArgs()
+
+	code, err := codon.Code(args.code)
+	if err != nil {
+		panic(err)
+	}
+
+	var reference *codon.Usage
+	if args.reference != "" {
+		reference = readReference(args.reference, code)
+	}
+
+	var input io.Reader = os.Stdin
+	if args.filename != "" {
+		file, err := fasta.Open(args.filename)
+		if err != nil {
+			panic(err)
+		}
+		input = file
+	}
+	records := fasta.Read(input)
+
+	genome := codon.NewUsage(code)
+	for str := range records {
+		if str.Error != nil {
+			panic(str.Error)
+		}
+
+		if reference != nil {
+			cai, err := reference.CAI(str.Sequence)
+			if err != nil {
+				panic(fmt.Errorf("%s: %s", str.Name, err))
+			}
+			fmt.Printf("%s\t%.4f\n", str.Name, cai)
+			continue
+		}
+
+		gene := codon.NewUsage(code)
+		if err := gene.Add(str.Sequence); err != nil {
+			panic(fmt.Errorf("%s: %s", str.Name, err))
+		}
+
+		if args.perGene {
+			fmt.Printf("# %s\n", str.Name)
+			gene.WriteTo(os.Stdout)
+		} else {
+			genome.Merge(gene)
+		}
+	}
+
+	if reference == nil && !args.perGene {
+		genome.WriteTo(os.Stdout)
+	}
+}
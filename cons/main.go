@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/tcsc/rosalind/fasta"
 	"os"
@@ -11,10 +12,13 @@ var bases = []uint8{'A', 'C', 'G', 'T'}
 type profile map[uint8][]int
 
 func main() {
+	asFasta := flag.Bool("fasta", false, "Write the consensus alone, as FASTA")
+	flag.Parse()
+
 	matrix := []string{}
 
 	// load the matrix
-	for s := range fasta.ReadFile(os.Args[1]) {
+	for s := range fasta.ReadFile(flag.Arg(0)) {
 		if s.Error != nil {
 			panic(s.Error)
 		}
@@ -28,6 +32,17 @@ func main() {
 	p := buildProfile(matrix)
 	c := buildConsensus(p)
 
+	if *asFasta {
+		w := fasta.NewWriter(os.Stdout, fasta.Width60)
+		if err := w.WriteRecord("consensus", "", string(c)); err != nil {
+			panic(err)
+		}
+		if err := w.Flush(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	for _, b := range c {
 		fmt.Printf("%c", b)
 	}
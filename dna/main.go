@@ -1,35 +1,55 @@
 package main
 
 import (
-	"bufio"
 	"fmt"
+	"github.com/tcsc/rosalind/basestring"
 	"io"
 	"os"
 )
 
+/// chunkSize is how much input is read, packed and counted at a time
+const chunkSize = 64 * 1024
+
+/// isBase picks out the bytes that are counted
+var isBase = [256]bool{'A': true, 'C': true, 'G': true, 'T': true}
+
 func main() {
-	totals := [4]int{0, 0, 0, 0}
-	stdin := bufio.NewReader(os.Stdin)
+	totals := countBases(os.Stdin)
+	fmt.Printf("%d %d %d %d", totals[0], totals[1], totals[2], totals[3])
+}
+
+/// countBases counts the A, C, G and T bases in the input a chunk at a time,
+/// so memory use doesn't grow with the input. Anything else, including
+/// lowercase bases and ambiguity codes, is skipped.
+func countBases(r io.Reader) [4]int {
+	var totals [4]int
+	buf := make([]byte, chunkSize)
+	bases := make([]byte, 0, chunkSize)
 	for {
-		ch, _, err := stdin.ReadRune()
-		if err == io.EOF {
-			break
+		n, err := r.Read(buf)
+		if err != nil && err != io.EOF {
+			panic(err)
 		}
 
-		if err != nil {
-			panic(err)
+		bases = bases[:0]
+		for _, c := range buf[:n] {
+			if isBase[c] {
+				bases = append(bases, c)
+			}
+		}
+		if len(bases) > 0 {
+			s, err := basestring.FromString(string(bases))
+			if err != nil {
+				panic(err)
+			}
+			composition := s.Composition()
+			for i, base := range "ACGT" {
+				totals[i] += composition[base]
+			}
 		}
 
-		switch ch {
-		case 'A':
-			totals[0]++
-		case 'C':
-			totals[1]++
-		case 'G':
-			totals[2]++
-		case 'T':
-			totals[3]++
+		if err == io.EOF {
+			return totals
 		}
 	}
-	fmt.Printf("%d %d %d %d", totals[0], totals[1], totals[2], totals[3])
 }
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_CountBasesSkipsEverythingButACGT(t *testing.T) {
+	input := "AGCTTTTCATTCTGACTGCA\nacgtNRX-12 \r\nAC"
+	expected := [4]int{5, 6, 3, 8}
+	if actual := countBases(strings.NewReader(input)); actual != expected {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,272 @@
+package fasta
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+/// gzipMagic starts every gzip (and so BGZF) file
+var gzipMagic = []byte{0x1f, 0x8b}
+
+/// CompressionError describes a compressed file that can't be used
+type CompressionError string
+
+func (self CompressionError) Error() string {
+	return fmt.Sprintf("Compressed FASTA: %s", string(self))
+}
+
+/// BGZFIndex maps offsets in the uncompressed data of a BGZF file onto the
+/// compressed blocks that hold them, as per a bgzip .gzi index.
+type BGZFIndex struct {
+	// blocks holds the compressed & uncompressed offset of the start of
+	// each block, in order. The first block (at 0, 0) is implied.
+	blocks []bgzfBlock
+}
+
+type bgzfBlock struct {
+	compressed   uint64
+	uncompressed uint64
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Decompressed is a buffered stream of uncompressed data, as returned by
+/// Decompress. Handing one back to Decompress (or to anything that calls it,
+/// such as a Scanner) returns it as is, so input is only sniffed once.
+type Decompressed struct {
+	*bufio.Reader
+
+	// gz is the gzip reader underneath, if the stream was compressed
+	gz *gzip.Reader
+}
+
+/// Open opens a file for reading, transparently decompressing it if it is
+/// gzip-compressed. BGZF files are valid gzip files, so are read the same
+/// way.
+func Open(filename string) (io.ReadCloser, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := Decompress(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if r.gz != nil {
+		return readCloser{r, closers{r.gz, file}}, nil
+	}
+	return readCloser{r, file}, nil
+}
+
+/// Decompress sniffs the start of a stream, returning a reader that
+/// decompresses it if it is gzip (or BGZF) compressed, and otherwise reads
+/// it as is. It never closes r.
+func Decompress(r io.Reader) (*Decompressed, error) {
+	switch v := r.(type) {
+	case *Decompressed:
+		return v, nil
+	case readCloser:
+		if d, ok := v.Reader.(*Decompressed); ok {
+			return d, nil
+		}
+	}
+
+	buffered := bufio.NewReader(r)
+	if !isGzip(buffered) {
+		return &Decompressed{Reader: buffered}, nil
+	}
+	gz, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	return &Decompressed{Reader: bufio.NewReader(gz), gz: gz}, nil
+}
+
+/// isGzip checks for the gzip magic bytes without consuming them
+func isGzip(r *bufio.Reader) bool {
+	magic, _ := r.Peek(len(gzipMagic))
+	return string(magic) == string(gzipMagic)
+}
+
+/// isBGZF checks whether the start of a file is a BGZF block header: a gzip
+/// header with a "BC" extra subfield.
+func isBGZF(header []byte) bool {
+	_, ok := bgzfBlockSize(header)
+	return ok
+}
+
+/// bgzfBlockSize parses a BGZF block header, returning the total size of
+/// the block in bytes
+func bgzfBlockSize(header []byte) (int, bool) {
+	const flagExtra = 4
+	if len(header) < 12 || string(header[:2]) != string(gzipMagic) ||
+		header[2] != 8 || header[3]&flagExtra == 0 {
+		return 0, false
+	}
+
+	xlen := int(binary.LittleEndian.Uint16(header[10:12]))
+	extra := header[12:]
+	if len(extra) < xlen {
+		return 0, false
+	}
+	extra = extra[:xlen]
+
+	for len(extra) >= 4 {
+		length := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if extra[0] == 'B' && extra[1] == 'C' && length == 2 && len(extra) >= 6 {
+			return int(binary.LittleEndian.Uint16(extra[4:6])) + 1, true
+		}
+		if len(extra) < 4+length {
+			break
+		}
+		extra = extra[4+length:]
+	}
+	return 0, false
+}
+
+/// BuildBGZFIndex indexes a BGZF file by walking its block headers. Only
+/// each block's header and footer are read; nothing is decompressed.
+func BuildBGZFIndex(r io.ReaderAt) (*BGZFIndex, error) {
+	result := &BGZFIndex{}
+	header := make([]byte, 18)
+	var compressed, uncompressed uint64
+	for {
+		n, err := r.ReadAt(header, int64(compressed))
+		if n == 0 && err == io.EOF {
+			return result, nil
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		size, ok := bgzfBlockSize(header[:n])
+		if !ok {
+			return nil, CompressionError(fmt.Sprintf("bad BGZF block at offset %d", compressed))
+		}
+
+		// the last four bytes of each block hold its uncompressed size
+		var isize [4]byte
+		if _, err := r.ReadAt(isize[:], int64(compressed)+int64(size)-4); err == io.EOF {
+			return nil, CompressionError(fmt.Sprintf("truncated BGZF block at offset %d", compressed))
+		} else if err != nil {
+			return nil, err
+		}
+		length := uint64(binary.LittleEndian.Uint32(isize[:]))
+
+		// bgzip leaves the first block and the empty end-of-file marker out
+		if compressed > 0 && length > 0 {
+			result.blocks = append(result.blocks, bgzfBlock{compressed, uncompressed})
+		}
+		compressed += uint64(size)
+		uncompressed += length
+	}
+}
+
+/// ReadBGZFIndex reads a bgzip .gzi index: a little-endian count of blocks,
+/// then the compressed & uncompressed offset of each.
+func ReadBGZFIndex(r io.Reader) (*BGZFIndex, error) {
+	var count uint64
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, CompressionError(fmt.Sprintf("bad .gzi index: %s", err))
+	}
+	if count > math.MaxInt32 {
+		return nil, CompressionError(fmt.Sprintf("implausible .gzi block count %d", count))
+	}
+
+	result := &BGZFIndex{}
+	for i := uint64(0); i < count; i++ {
+		var block [2]uint64
+		if err := binary.Read(r, binary.LittleEndian, &block); err != nil {
+			return nil, CompressionError(fmt.Sprintf("bad .gzi index: %s", err))
+		}
+		b := bgzfBlock{block[0], block[1]}
+		if n := len(result.blocks); n > 0 && (b.compressed <= result.blocks[n-1].compressed ||
+			b.uncompressed < result.blocks[n-1].uncompressed) {
+			return nil, CompressionError(".gzi blocks out of order")
+		}
+		result.blocks = append(result.blocks, b)
+	}
+	return result, nil
+}
+
+/// WriteTo writes the index out in the bgzip .gzi format
+func (self *BGZFIndex) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, 8*(1+2*len(self.blocks)))
+	binary.LittleEndian.PutUint64(buf, uint64(len(self.blocks)))
+	for i, b := range self.blocks {
+		binary.LittleEndian.PutUint64(buf[8+16*i:], b.compressed)
+		binary.LittleEndian.PutUint64(buf[16+16*i:], b.uncompressed)
+	}
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+/// bgzfReaderAt provides random access to the uncompressed contents of a
+/// BGZF file
+type bgzfReaderAt struct {
+	r     io.ReaderAt
+	index *BGZFIndex
+}
+
+/// ReadAt implements io.ReaderAt, decompressing from the start of the
+/// block holding off.
+func (self bgzfReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, CompressionError(fmt.Sprintf("negative offset %d", off))
+	}
+
+	// find the last block starting at or before off
+	blocks := self.index.blocks
+	i := sort.Search(len(blocks), func(i int) bool {
+		return blocks[i].uncompressed > uint64(off)
+	})
+	start := bgzfBlock{}
+	if i > 0 {
+		start = blocks[i-1]
+	}
+
+	section := io.NewSectionReader(self.r, int64(start.compressed), math.MaxInt64-int64(start.compressed))
+	gz, err := gzip.NewReader(section)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	if _, err := io.CopyN(io.Discard, gz, off-int64(start.uncompressed)); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(gz, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+/// readCloser pairs a reader with the Closer of what it reads
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+/// closers closes several things in order, returning the first error
+type closers []io.Closer
+
+func (self closers) Close() error {
+	var result error
+	for _, c := range self {
+		if err := c.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
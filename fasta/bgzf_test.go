@@ -0,0 +1,216 @@
+package fasta
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/// bgzf compresses text as bgzip would, but with a block for each chunk
+/// so that tests can control where the boundaries fall
+func bgzf(chunks ...string) []byte {
+	var result bytes.Buffer
+	for _, chunk := range append(chunks, "") {
+		var data bytes.Buffer
+		w, _ := flate.NewWriter(&data, flate.BestCompression)
+		w.Write([]byte(chunk))
+		w.Close()
+
+		header := []byte{0x1f, 0x8b, 8, 4, 0, 0, 0, 0, 0, 0xff, 6, 0, 'B', 'C', 2, 0, 0, 0}
+		binary.LittleEndian.PutUint16(header[16:], uint16(len(header)+data.Len()+8-1))
+		result.Write(header)
+		result.Write(data.Bytes())
+		binary.Write(&result, binary.LittleEndian, crc32.ChecksumIEEE([]byte(chunk)))
+		binary.Write(&result, binary.LittleEndian, uint32(len(chunk)))
+	}
+	return result.Bytes()
+}
+
+func gzipped(text string) []byte {
+	var result bytes.Buffer
+	w := gzip.NewWriter(&result)
+	w.Write([]byte(text))
+	w.Close()
+	return result.Bytes()
+}
+
+func writeTestFile(t *testing.T, data []byte) string {
+	filename := filepath.Join(t.TempDir(), "test.fa.gz")
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func Test_ReadFileDecompressesTransparently(t *testing.T) {
+	files := map[string][]byte{
+		"plain": []byte(indexedFasta),
+		"gzip":  gzipped(indexedFasta),
+		"bgzf":  bgzf(indexedFasta[:17], indexedFasta[17:45], indexedFasta[45:]),
+	}
+
+	expected := []String{}
+	for s := range Read(strings.NewReader(indexedFasta)) {
+		expected = append(expected, s)
+	}
+
+	for kind, data := range files {
+		actual := []String{}
+		for s := range ReadFile(writeTestFile(t, data)) {
+			actual = append(actual, s)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v from %s file, got %v", expected, kind, actual)
+		}
+	}
+}
+
+func Test_ReadDecompressesStreams(t *testing.T) {
+	for kind, data := range map[string][]byte{"gzip": gzipped(indexedFasta), "bgzf": bgzf(indexedFasta)} {
+		count := 0
+		for s := range Read(bytes.NewReader(data)) {
+			if s.Error != nil {
+				t.Fatalf("Unexpected error reading %s: %s", kind, s.Error.Error())
+			}
+			count++
+		}
+		if count != 3 {
+			t.Errorf("Expected 3 records from %s stream, got %d", kind, count)
+		}
+	}
+}
+
+func Test_DecompressOnlySniffsOnce(t *testing.T) {
+	filename := writeTestFile(t, gzipped(indexedFasta))
+	file, err := Open(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer file.Close()
+
+	first, err := Decompress(file)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if second, _ := Decompress(first); second != first {
+		t.Error("Expected decompressing twice to return the same reader")
+	}
+	if first.gz == nil {
+		t.Error("Expected the file to be decompressed")
+	}
+}
+
+type failingReaderAt struct{}
+
+func (self failingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func Test_BuildBGZFIndexReportsReadErrors(t *testing.T) {
+	_, err := BuildBGZFIndex(failingReaderAt{})
+	if err == nil || err.Error() != "disk on fire" {
+		t.Errorf("Expected the read error, got %v", err)
+	}
+}
+
+func Test_BGZFIndexMatchesBgzip(t *testing.T) {
+	data := bgzf("ACGT", "", "GGCCAA", "TT")
+	index, err := BuildBGZFIndex(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	// neither the first block, the empty block nor the end marker are listed
+	blockSize := func(i int) uint64 {
+		size, _ := bgzfBlockSize(data[i:])
+		return uint64(size)
+	}
+	first := blockSize(0)
+	second := first + blockSize(int(first))
+	third := second + blockSize(int(second))
+	expected := []bgzfBlock{{second, 4}, {third, 10}}
+	if !reflect.DeepEqual(index.blocks, expected) {
+		t.Errorf("Expected %v, got %v", expected, index.blocks)
+	}
+
+	var buf bytes.Buffer
+	index.WriteTo(&buf)
+	if buf.Len() != 8+16*len(expected) {
+		t.Errorf("Expected %d bytes of .gzi, got %d", 8+16*len(expected), buf.Len())
+	}
+	read, err := ReadBGZFIndex(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(read, index) {
+		t.Errorf("Expected %v, got %v", index, read)
+	}
+
+	if _, err := BuildBGZFIndex(bytes.NewReader(gzipped("ACGT"))); err == nil {
+		t.Errorf("Expected an error indexing a plain gzip file")
+	}
+}
+
+func Test_FetchReadsAcrossBGZFBlocks(t *testing.T) {
+	filename := writeTestFile(t, bgzf(indexedFasta[:17], indexedFasta[17:23], indexedFasta[23:45], indexedFasta[45:]))
+
+	check := func(f *IndexedFile) {
+		sequences := map[string]string{"one": "ACGTACGTACGT", "two": "GGGGCCCCAA", "three": "TTTTTT"}
+		for name, seq := range sequences {
+			for start := 0; start <= len(seq); start++ {
+				for end := start; end <= len(seq); end++ {
+					actual, err := f.Fetch(name, int64(start), int64(end))
+					if err != nil {
+						t.Fatalf("Unexpected error: %s", err.Error())
+					}
+					if actual != seq[start:end] {
+						t.Errorf("Expected %s[%d:%d] to be %q, got %q", name, start, end, seq[start:end], actual)
+					}
+				}
+			}
+		}
+	}
+
+	// without any index files, both indices are built on the fly
+	f, err := OpenIndexed(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	check(f)
+	f.Close()
+
+	if _, err := IndexFile(filename); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	fai, _ := os.ReadFile(filename + ".fai")
+	if !strings.HasPrefix(string(fai), "one\t12\t20\t5\t6\n") {
+		t.Errorf("Unexpected index %q", fai)
+	}
+	if _, err := os.Stat(filename + ".gzi"); err != nil {
+		t.Errorf("Expected a .gzi index: %s", err.Error())
+	}
+
+	f, err = OpenIndexed(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer f.Close()
+	check(f)
+}
+
+func Test_OpenIndexedRejectsPlainGzip(t *testing.T) {
+	filename := writeTestFile(t, gzipped(indexedFasta))
+	if _, err := OpenIndexed(filename); err == nil {
+		t.Errorf("Expected an error")
+	} else if _, ok := err.(CompressionError); !ok {
+		t.Errorf("Expected CompressionError, got %v", err)
+	}
+}
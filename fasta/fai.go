@@ -0,0 +1,427 @@
+package fasta
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+/// IndexEntry locates one sequence in a FASTA file, as per a line of a
+/// samtools .fai index
+type IndexEntry struct {
+	/// Name is the sequence name, up to the first whitespace in its header
+	Name string
+
+	/// Length is the number of bases in the sequence
+	Length int64
+
+	/// Offset is the byte offset of the first base in the file
+	Offset int64
+
+	/// LineBases is the number of bases on each full line
+	LineBases int64
+
+	/// LineWidth is the number of bytes in each full line, including its
+	/// line ending
+	LineWidth int64
+}
+
+/// Index is a samtools-compatible index of a FASTA file
+type Index struct {
+	entries []IndexEntry
+	byName  map[string]int
+}
+
+/// IndexFormatError describes a FASTA file that can't be indexed, or a
+/// malformed .fai file
+type IndexFormatError struct {
+	Line int
+	Msg  string
+}
+
+func (self IndexFormatError) Error() string {
+	return fmt.Sprintf("FASTA index line %d: %s", self.Line, self.Msg)
+}
+
+/// UnknownSequenceError is returned when fetching a sequence that isn't in
+/// the index
+type UnknownSequenceError string
+
+func (self UnknownSequenceError) Error() string {
+	return fmt.Sprintf("No such sequence: %s", string(self))
+}
+
+/// StaleIndexError is returned when the index entry for a sequence doesn't
+/// match the file's contents, usually because the file changed after it was
+/// indexed
+type StaleIndexError string
+
+func (self StaleIndexError) Error() string {
+	return fmt.Sprintf("Index doesn't match the data for %s", string(self))
+}
+
+/// RangeError is returned when fetching a region that isn't entirely within
+/// its sequence
+type RangeError struct {
+	Name   string
+	Start  int64
+	End    int64
+	Length int64
+}
+
+func (self RangeError) Error() string {
+	return fmt.Sprintf("Region [%d:%d) out of range for %s (length %d)",
+		self.Start, self.End, self.Name, self.Length)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// BuildIndex indexes FASTA text. Every line of a sequence but the last must
+/// hold the same number of bases, and have the same line ending.
+func BuildIndex(reader io.Reader) (*Index, error) {
+	index := newIndex()
+	r := bufio.NewReader(reader)
+
+	var entry *IndexEntry
+	var offset int64
+	line := 0
+	short := false
+	for {
+		text, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(text) == 0 {
+			break
+		}
+		line++
+		width := int64(len(text))
+		offset += width
+		bases := int64(len(bytes.TrimRight(text, "\r\n")))
+
+		switch {
+		case text[0] == '>':
+			name := strings.Fields(string(text[1:]))
+			if len(name) == 0 {
+				return nil, IndexFormatError{line, "sequence has no name"}
+			}
+
+			// the entry is filled in in place as its lines are read
+			if err := index.add(IndexEntry{Name: name[0], Offset: offset}, line); err != nil {
+				return nil, err
+			}
+			entry = &index.entries[len(index.entries)-1]
+			short = false
+
+		case entry == nil:
+			if bases > 0 {
+				return nil, IndexFormatError{line, "sequence data before the first header"}
+			}
+
+		case text[0] == ';':
+			return nil, IndexFormatError{line, "comments can't be indexed"}
+
+		case bases == 0:
+			short = true
+
+		case short:
+			return nil, IndexFormatError{line, fmt.Sprintf("inconsistent line lengths in %s", entry.Name)}
+
+		default:
+			// the last line may be missing its line ending
+			if err == io.EOF && width == bases {
+				width = bases + max(entry.LineWidth-entry.LineBases, 1)
+			}
+			if entry.LineBases == 0 {
+				entry.LineBases, entry.LineWidth = bases, width
+			} else if bases > entry.LineBases || width-bases != entry.LineWidth-entry.LineBases {
+				return nil, IndexFormatError{line, fmt.Sprintf("inconsistent line lengths in %s", entry.Name)}
+			}
+			short = bases < entry.LineBases
+			entry.Length += bases
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return index, nil
+}
+
+/// ReadIndex reads an index in the samtools .fai format
+func ReadIndex(reader io.Reader) (*Index, error) {
+	index := newIndex()
+	s := bufio.NewScanner(reader)
+	line := 0
+	for s.Scan() {
+		line++
+		if strings.TrimSpace(s.Text()) == "" {
+			continue
+		}
+
+		fields := strings.Split(s.Text(), "\t")
+		if len(fields) < 5 {
+			return nil, IndexFormatError{line, fmt.Sprintf("expected 5 fields, got %d", len(fields))}
+		}
+
+		values := [4]int64{}
+		for i := range values {
+			v, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil || v < 0 {
+				return nil, IndexFormatError{line, fmt.Sprintf("bad number %q", fields[i+1])}
+			}
+			values[i] = v
+		}
+
+		entry := IndexEntry{fields[0], values[0], values[1], values[2], values[3]}
+		if entry.LineWidth < entry.LineBases || (entry.LineBases == 0 && entry.Length > 0) {
+			return nil, IndexFormatError{line, "bad line length"}
+		}
+		if err := index.add(entry, line); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+func newIndex() *Index {
+	return &Index{byName: map[string]int{}}
+}
+
+func (self *Index) add(entry IndexEntry, line int) error {
+	if _, ok := self.byName[entry.Name]; ok {
+		return IndexFormatError{line, fmt.Sprintf("duplicate sequence name %s", entry.Name)}
+	}
+	self.byName[entry.Name] = len(self.entries)
+	self.entries = append(self.entries, entry)
+	return nil
+}
+
+/// WriteTo writes the index out in the samtools .fai format
+func (self *Index) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	for _, e := range self.entries {
+		fmt.Fprintf(&buf, "%s\t%d\t%d\t%d\t%d\n", e.Name, e.Length, e.Offset, e.LineBases, e.LineWidth)
+	}
+	return buf.WriteTo(w)
+}
+
+/// Entries returns the indexed sequences, in file order
+func (self *Index) Entries() []IndexEntry {
+	return append([]IndexEntry{}, self.entries...)
+}
+
+/// Lookup finds the entry for a named sequence
+func (self *Index) Lookup(name string) (IndexEntry, bool) {
+	i, ok := self.byName[name]
+	if !ok {
+		return IndexEntry{}, false
+	}
+	return self.entries[i], true
+}
+
+/// position returns the byte offset in the file of the base at index i
+func (self IndexEntry) position(i int64) int64 {
+	if self.LineBases == 0 {
+		return self.Offset
+	}
+	return self.Offset + (i/self.LineBases)*self.LineWidth + i%self.LineBases
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// IndexedFile gives random access to the sequences in an indexed FASTA file
+type IndexedFile struct {
+	r      io.ReaderAt
+	index  *Index
+	closer io.Closer
+}
+
+/// NewIndexedFile provides random access to FASTA data using an index
+func NewIndexedFile(r io.ReaderAt, index *Index) *IndexedFile {
+	return &IndexedFile{r: r, index: index}
+}
+
+/// IndexFile builds an index for the named FASTA file, and writes it
+/// alongside as filename.fai. If the file is BGZF-compressed its .gzi index
+/// is written too.
+func IndexFile(filename string) (*Index, error) {
+	file, blocks, err := openBlocks(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if blocks != nil {
+		if err := writeIndex(filename+".gzi", blocks); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	index, err := BuildIndex(r)
+	if err != nil {
+		return nil, err
+	}
+	return index, writeIndex(filename+".fai", index)
+}
+
+func writeIndex(filename string, index io.WriterTo) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := index.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+/// OpenIndexed opens a FASTA file for random access, using its .fai index.
+/// If there is no index, one is built in memory (but not saved). The file
+/// may be BGZF-compressed, in which case its .gzi index is used in the same
+/// way.
+func OpenIndexed(filename string) (*IndexedFile, error) {
+	file, blocks, err := openBlocks(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.ReaderAt = file
+	if blocks != nil {
+		r = bgzfReaderAt{file, blocks}
+	}
+
+	index, err := openIndex(filename)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &IndexedFile{r: r, index: index, closer: file}, nil
+}
+
+/// openBlocks opens a FASTA file for random access. If it is BGZF-compressed
+/// the block index is read from its .gzi file, or built if there isn't one;
+/// otherwise the returned index is nil. Other gzip files can't be read at
+/// random.
+func openBlocks(filename string) (*os.File, *BGZFIndex, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make([]byte, 18)
+	n, _ := file.ReadAt(header, 0)
+	switch {
+	case isBGZF(header[:n]):
+		blocks, err := openBGZFIndex(filename, file)
+		if err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+		return file, blocks, nil
+
+	case n >= len(gzipMagic) && bytes.Equal(header[:len(gzipMagic)], gzipMagic):
+		file.Close()
+		return nil, nil, CompressionError(fmt.Sprintf("%s is gzipped, not BGZF; recompress it with bgzip", filename))
+	}
+	return file, nil, nil
+}
+
+func openBGZFIndex(filename string, file *os.File) (*BGZFIndex, error) {
+	gzi, err := os.Open(filename + ".gzi")
+	if os.IsNotExist(err) {
+		return BuildBGZFIndex(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer gzi.Close()
+	return ReadBGZFIndex(bufio.NewReader(gzi))
+}
+
+func openIndex(filename string) (*Index, error) {
+	fai, err := os.Open(filename + ".fai")
+	if os.IsNotExist(err) {
+		r, err := Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return BuildIndex(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer fai.Close()
+	return ReadIndex(fai)
+}
+
+/// Index returns the file's index
+func (self *IndexedFile) Index() *Index {
+	return self.index
+}
+
+/// Fetch reads the bases in [start, end) of the named sequence, counting
+/// from zero, reading only the bytes that hold them.
+func (self *IndexedFile) Fetch(name string, start, end int64) (string, error) {
+	entry, ok := self.index.Lookup(name)
+	if !ok {
+		return "", UnknownSequenceError(name)
+	}
+	if start < 0 || end < start || end > entry.Length {
+		return "", RangeError{name, start, end, entry.Length}
+	}
+	if start == end {
+		return "", nil
+	}
+
+	from := entry.position(start)
+	to := entry.position(end-1) + 1
+	buf := make([]byte, to-from)
+	if n, err := self.r.ReadAt(buf, from); n < len(buf) {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return "", err
+	}
+
+	// strip out the line endings
+	result := buf[:0]
+	for _, c := range buf {
+		if c != '\n' && c != '\r' {
+			result = append(result, c)
+		}
+	}
+	if int64(len(result)) != end-start {
+		return "", StaleIndexError(name)
+	}
+	return string(result), nil
+}
+
+/// Close closes the underlying file, if the IndexedFile opened it
+func (self *IndexedFile) Close() error {
+	if self.closer != nil {
+		return self.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package fasta
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const indexedFasta = ">one first sequence\nACGTA\nCGTAC\nGT\n>two\nGGGGCCCC\nAA\n>three\r\nTTTT\r\nTT\r\n"
+
+func Test_BuildIndexMatchesSamtools(t *testing.T) {
+	index, err := BuildIndex(strings.NewReader(indexedFasta))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+
+	expected := []IndexEntry{
+		{"one", 12, 20, 5, 6},
+		{"two", 10, 40, 8, 9},
+		{"three", 6, 60, 4, 6},
+	}
+	if !reflect.DeepEqual(index.Entries(), expected) {
+		t.Errorf("Expected %v, got %v", expected, index.Entries())
+	}
+
+	var buf bytes.Buffer
+	index.WriteTo(&buf)
+	fai := "one\t12\t20\t5\t6\ntwo\t10\t40\t8\t9\nthree\t6\t60\t4\t6\n"
+	if buf.String() != fai {
+		t.Errorf("Expected %q, got %q", fai, buf.String())
+	}
+}
+
+func Test_BuildIndexHandlesMissingFinalNewline(t *testing.T) {
+	for _, text := range []string{">a\nACGT\nAC", ">a\nACGTAC"} {
+		index, err := BuildIndex(strings.NewReader(text))
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %s", text, err.Error())
+		}
+		if e, _ := index.Lookup("a"); e.Length != 6 {
+			t.Errorf("Expected length 6 for %q, got %d", text, e.Length)
+		}
+	}
+}
+
+func Test_BuildIndexRejectsUnindexableFiles(t *testing.T) {
+	tests := []struct {
+		text string
+		line int
+	}{
+		{">a\nACGT\nAC\nACGT\n", 4},
+		{">a\nACGT\nACGTA\n", 3},
+		{">a\nACGT\n\nACGT\n", 4},
+		{">a\nACGT\r\nACGT\n", 3},
+		{">a\nACGT\n>a\nACGT\n", 3},
+		{"ACGT\n>a\nACGT\n", 1},
+		{">a\nACGT\n;comment\n", 3},
+		{">\nACGT\n", 1},
+	}
+	for _, test := range tests {
+		_, err := BuildIndex(strings.NewReader(test.text))
+		e, ok := err.(IndexFormatError)
+		if !ok {
+			t.Errorf("Expected IndexFormatError for %q, got %v", test.text, err)
+		} else if e.Line != test.line {
+			t.Errorf("Expected error on line %d for %q, got %d", test.line, test.text, e.Line)
+		}
+	}
+}
+
+func Test_ReadIndexRoundTrips(t *testing.T) {
+	built, _ := BuildIndex(strings.NewReader(indexedFasta))
+	var buf bytes.Buffer
+	built.WriteTo(&buf)
+
+	read, err := ReadIndex(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if !reflect.DeepEqual(read.Entries(), built.Entries()) {
+		t.Errorf("Expected %v, got %v", built.Entries(), read.Entries())
+	}
+
+	for _, bad := range []string{"a\t1\t2\t3\n", "a\t1\tx\t3\t4\n", "a\t1\t2\t5\t4\n", "a\t1\t2\t3\t4\na\t1\t2\t3\t4\n"} {
+		if _, err := ReadIndex(strings.NewReader(bad)); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func Test_FetchReadsAcrossLines(t *testing.T) {
+	index, _ := BuildIndex(strings.NewReader(indexedFasta))
+	f := NewIndexedFile(strings.NewReader(indexedFasta), index)
+
+	sequences := map[string]string{"one": "ACGTACGTACGT", "two": "GGGGCCCCAA", "three": "TTTTTT"}
+	for name, seq := range sequences {
+		for start := 0; start <= len(seq); start++ {
+			for end := start; end <= len(seq); end++ {
+				actual, err := f.Fetch(name, int64(start), int64(end))
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err.Error())
+				}
+				if actual != seq[start:end] {
+					t.Errorf("Expected %s[%d:%d] to be %q, got %q", name, start, end, seq[start:end], actual)
+				}
+			}
+		}
+	}
+}
+
+func Test_FetchReportsBadRequests(t *testing.T) {
+	index, _ := BuildIndex(strings.NewReader(indexedFasta))
+	f := NewIndexedFile(strings.NewReader(indexedFasta), index)
+
+	if _, err := f.Fetch("four", 0, 1); err != UnknownSequenceError("four") {
+		t.Errorf("Expected UnknownSequenceError, got %v", err)
+	}
+	for _, r := range [][2]int64{{-1, 2}, {3, 2}, {0, 13}, {12, 13}} {
+		_, err := f.Fetch("one", r[0], r[1])
+		if err != (RangeError{"one", r[0], r[1], 12}) {
+			t.Errorf("Expected RangeError for %v, got %v", r, err)
+		}
+	}
+}
+
+func Test_FetchDetectsStaleIndex(t *testing.T) {
+	index, _ := BuildIndex(strings.NewReader(indexedFasta))
+
+	// the same file with the first sequence's lines shortened
+	changed := ">one first sequence\nACG\nTAC\nGTA\nCGT\n>two\nGGGGCCCC\nAA\n"
+	f := NewIndexedFile(strings.NewReader(changed), index)
+	if _, err := f.Fetch("one", 0, 8); err != StaleIndexError("one") {
+		t.Errorf("Expected StaleIndexError, got %v", err)
+	}
+}
+
+func Test_OpenIndexedUsesFaiFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "test.fa")
+	if err := os.WriteFile(filename, []byte(indexedFasta), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// without a .fai file, the index is built on the fly
+	f, err := OpenIndexed(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if s, _ := f.Fetch("two", 6, 10); s != "CCAA" {
+		t.Errorf("Expected \"CCAA\", got %q", s)
+	}
+	f.Close()
+
+	if _, err := IndexFile(filename); err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	fai, _ := os.ReadFile(filename + ".fai")
+	if !strings.HasPrefix(string(fai), "one\t12\t20\t5\t6\n") {
+		t.Errorf("Unexpected index %q", fai)
+	}
+
+	// a deliberately wrong index shows that it's the one being used
+	os.WriteFile(filename+".fai", []byte("one\t3\t0\t3\t4\n"), 0644)
+	f, err = OpenIndexed(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	defer f.Close()
+	if s, _ := f.Fetch("one", 0, 3); s != ">on" {
+		t.Errorf("Expected \">on\", got %q", s)
+	}
+}
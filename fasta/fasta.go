@@ -1,11 +1,8 @@
 package fasta
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"io"
-	"os"
-	"strings"
 )
 
 type String struct {
@@ -14,8 +11,10 @@ type String struct {
 	Error    error
 }
 
+/// ReadFile streams the records in the named file, as per Read. The file
+/// may be gzip or BGZF compressed.
 func ReadFile(filename string) <-chan String {
-	file, err := os.Open(filename)
+	file, err := Open(filename)
 	if err != nil {
 		ch := make(chan String, 1)
 		ch <- String{"", "", err}
@@ -25,7 +24,18 @@ func ReadFile(filename string) <-chan String {
 	return Read(file)
 }
 
+/// Read streams the records from a reader over a channel, closing the
+/// reader when done if it is an io.Closer. The input may be gzip or BGZF
+/// compressed. Any error is delivered as the last record, with its Error
+/// set. The channel must be drained; a consumer that might stop early
+/// should use ReadContext and cancel it, or a Scanner.
 func Read(reader io.Reader) <-chan String {
+	return ReadContext(context.Background(), reader)
+}
+
+/// ReadContext is Read, but gives up (closing the channel and reader) once
+/// ctx is done.
+func ReadContext(ctx context.Context, reader io.Reader) <-chan String {
 	ch := make(chan String, 2)
 	go func() {
 		// When we exit, close the input stream if its closeable
@@ -38,33 +48,12 @@ func Read(reader io.Reader) <-chan String {
 		// When we exit, close the channel back to the caller
 		defer close(ch)
 
-		name := ""
-		var seq bytes.Buffer
-		s := bufio.NewScanner(reader)
-		for s.Scan() {
-			if s.Err() != nil {
-				ch <- String{"", "", s.Err()}
+		for record := range Records(ctx, reader) {
+			select {
+			case ch <- record:
+			case <-ctx.Done():
 				return
 			}
-
-			line := strings.TrimSpace(s.Text())
-			if len(line) == 0 || line[0] == ';' {
-				continue
-			}
-
-			if line[0] == '>' {
-				if seq.Len() > 0 {
-					ch <- String{name, seq.String(), nil}
-				}
-				name = line[1:]
-				seq.Reset()
-			} else {
-				seq.WriteString(line)
-			}
-		}
-
-		if seq.Len() > 0 {
-			ch <- String{name, seq.String(), nil}
 		}
 	}()
 
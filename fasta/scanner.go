@@ -0,0 +1,143 @@
+package fasta
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"iter"
+)
+
+/// Scanner reads FASTA records one at a time, on the caller's goroutine.
+/// Lines starting with ';' are comments, and records with no sequence are
+/// skipped. Gzip-compressed input is decompressed transparently. A typical
+/// loop is:
+///
+///	s := fasta.NewScanner(r)
+///	for s.Next() {
+///	    record := s.Record()
+///	    ...
+///	}
+///	if err := s.Err(); err != nil {
+///	    ...
+///	}
+type Scanner struct {
+	ctx context.Context
+	s   *bufio.Scanner
+
+	// name is the header of the record being read; current is the header
+	// of the record last returned by Next
+	name    string
+	current string
+	seq     bytes.Buffer
+
+	err  error
+	done bool
+}
+
+/// maxLineLength is the longest line a Scanner will accept. Whole
+/// chromosomes are often written on a single line, so this is far beyond
+/// bufio's default.
+const maxLineLength = 1 << 30
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// NewScanner creates a Scanner reading from r, which may be gzip or BGZF
+/// compressed. The Scanner doesn't close r.
+func NewScanner(r io.Reader) *Scanner {
+	return NewScannerContext(context.Background(), r)
+}
+
+/// NewScannerContext creates a Scanner that stops, with the context's error,
+/// once ctx is done.
+func NewScannerContext(ctx context.Context, r io.Reader) *Scanner {
+	result := &Scanner{ctx: ctx}
+	if input, err := Decompress(r); err != nil {
+		result.err = err
+	} else {
+		result.s = bufio.NewScanner(input)
+		result.s.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	}
+	return result
+}
+
+/// Next advances to the next record, returning false at the end of the
+/// input or on an error.
+func (self *Scanner) Next() bool {
+	if self.done || self.err != nil {
+		return false
+	}
+
+	self.seq.Reset()
+	for self.s.Scan() {
+		if err := self.ctx.Err(); err != nil {
+			self.err = err
+			return false
+		}
+
+		line := bytes.TrimSpace(self.s.Bytes())
+		if len(line) == 0 || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '>' {
+			name := string(line[1:])
+			if self.seq.Len() > 0 {
+				self.current, self.name = self.name, name
+				return true
+			}
+			self.name = name
+		} else {
+			self.seq.Write(line)
+		}
+	}
+
+	self.done = true
+	if err := self.s.Err(); err != nil {
+		self.err = err
+		return false
+	}
+	self.current = self.name
+	return self.seq.Len() > 0
+}
+
+/// Record returns the record found by the last call to Next
+func (self *Scanner) Record() String {
+	return String{self.current, self.seq.String(), nil}
+}
+
+/// Name returns the header line, without its '>', of the current record
+func (self *Scanner) Name() string {
+	return self.current
+}
+
+/// Sequence returns the sequence of the current record without copying it.
+/// The slice is only valid until the next call to Next.
+func (self *Scanner) Sequence() []byte {
+	return self.seq.Bytes()
+}
+
+/// Err returns the error, if any, that stopped the Scanner
+func (self *Scanner) Err() error {
+	return self.err
+}
+
+/// Records iterates over the records in r, reading them synchronously as
+/// the loop asks for them, so it's safe to break out early. An error ends
+/// the iteration, delivered both as the error and in the String's Error
+/// field.
+func Records(ctx context.Context, r io.Reader) iter.Seq2[String, error] {
+	return func(yield func(String, error) bool) {
+		s := NewScannerContext(ctx, r)
+		for s.Next() {
+			if !yield(s.Record(), nil) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(String{"", "", err}, err)
+		}
+	}
+}
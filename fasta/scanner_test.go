@@ -0,0 +1,160 @@
+package fasta
+
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+const scannerFasta = ">one first\nGAT\nTACA\n\n;comment\n>empty\n>two\r\nACGT\r\n>three\nTT"
+
+func Test_ScannerReadsRecordsInOrder(t *testing.T) {
+	s := NewScanner(strings.NewReader(scannerFasta))
+	expected := []String{
+		{"one first", "GATTACA", nil},
+		{"two", "ACGT", nil},
+		{"three", "TT", nil},
+	}
+
+	for i, e := range expected {
+		if !s.Next() {
+			t.Fatalf("Expected record %d, got end of input (%v)", i, s.Err())
+		}
+		if r := s.Record(); r != e {
+			t.Errorf("Expected %#v, got %#v", e, r)
+		}
+		if s.Name() != e.Name || string(s.Sequence()) != e.Sequence {
+			t.Errorf("Expected %s %s, got %s %s", e.Name, e.Sequence, s.Name(), s.Sequence())
+		}
+	}
+
+	if s.Next() {
+		t.Errorf("Unexpected extra record %#v", s.Record())
+	}
+	if s.Err() != nil {
+		t.Errorf("Unexpected error: %s", s.Err().Error())
+	}
+}
+
+func Test_ScannerReadsLinesLongerThan64K(t *testing.T) {
+	seq := strings.Repeat("ACGT", 25000)
+	s := NewScanner(strings.NewReader(">chr1\n" + seq + "\n>chr2\nAC\n"))
+
+	if !s.Next() || s.Name() != "chr1" || string(s.Sequence()) != seq {
+		t.Fatalf("Expected the %d base chr1, got %v", len(seq), s.Err())
+	}
+	if !s.Next() || s.Record() != (String{"chr2", "AC", nil}) {
+		t.Errorf("Expected chr2 after the long line, got %v", s.Err())
+	}
+}
+
+func Test_ScannerMatchesChannelReader(t *testing.T) {
+	tests := []string{"", ">SomeName\nGAT\nTACA", "GAT\n>a\nA", ">a\n>b\nC\n"}
+	for _, text := range tests {
+		expected := []String{}
+		for r := range Read(strings.NewReader(text)) {
+			expected = append(expected, r)
+		}
+
+		actual := []String{}
+		for r, err := range Records(context.Background(), strings.NewReader(text)) {
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			actual = append(actual, r)
+		}
+
+		if len(actual) != len(expected) {
+			t.Fatalf("Expected %v for %q, got %v", expected, text, actual)
+		}
+		for i := range actual {
+			if actual[i] != expected[i] {
+				t.Errorf("Expected %#v for %q, got %#v", expected[i], text, actual[i])
+			}
+		}
+	}
+}
+
+func Test_ScannerReportsReadErrors(t *testing.T) {
+	failure := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader(">a\nACGT\n>b\nAC"), iotest.ErrReader(failure))
+
+	s := NewScanner(r)
+	if !s.Next() || s.Record().Name != "a" {
+		t.Fatalf("Expected record a before the error")
+	}
+	if s.Next() {
+		t.Errorf("Expected the error to stop the scanner, got %#v", s.Record())
+	}
+	if s.Err() != failure {
+		t.Errorf("Expected %v, got %v", failure, s.Err())
+	}
+
+	// the channel reader delivers the error as the last record
+	var last String
+	for last = range Read(io.MultiReader(strings.NewReader(">a\nACGT\n"), iotest.ErrReader(failure))) {
+	}
+	if last.Error != failure {
+		t.Errorf("Expected %v, got %#v", failure, last)
+	}
+}
+
+func Test_ScannerStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScannerContext(ctx, strings.NewReader(scannerFasta))
+	if !s.Next() {
+		t.Fatalf("Expected a record")
+	}
+	cancel()
+	if s.Next() {
+		t.Errorf("Expected cancellation to stop the scanner")
+	}
+	if s.Err() != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, s.Err())
+	}
+}
+
+func Test_RecordsCanBeAbandoned(t *testing.T) {
+	count := 0
+	for range Records(context.Background(), strings.NewReader(scannerFasta)) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 record, got %d", count)
+	}
+}
+
+func Test_CancellingReadContextReleasesTheReader(t *testing.T) {
+	// enough records to fill the channel's buffer
+	text := strings.Repeat(">a\nACGT\n", 100)
+
+	before := runtime.NumGoroutine()
+	signal := make(chan bool, 1)
+	reader := signallingReader{reader: strings.NewReader(text), signal: signal}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	records := ReadContext(ctx, &reader)
+	<-records
+	cancel()
+
+	select {
+	case <-signal:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the reader to be closed")
+	}
+
+	for range records {
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		time.Sleep(10 * time.Millisecond)
+		if after = runtime.NumGoroutine(); after > before {
+			t.Errorf("Expected %d goroutines, got %d", before, after)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package fasta
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/// Common line widths for wrapping sequences. Unwrapped writes each sequence
+/// on a single line.
+const (
+	Unwrapped = 0
+	Width60   = 60
+	Width70   = 70
+	Width80   = 80
+)
+
+/// InvalidHeaderError is returned when asked to write a record whose name or
+/// description would break the header line
+type InvalidHeaderError string
+
+func (self InvalidHeaderError) Error() string {
+	return fmt.Sprintf("Invalid FASTA header: %q", string(self))
+}
+
+/// LineWidthError is returned when writing with a negative Width
+type LineWidthError int
+
+func (self LineWidthError) Error() string {
+	return fmt.Sprintf("Invalid FASTA line width: %d", int(self))
+}
+
+/// Writer writes FASTA records, wrapping sequences at a fixed width. Output is
+/// buffered, so Flush must be called once all the records are written.
+///
+/// Reading a file and writing its records back out with a Writer of the same
+/// width reproduces the file exactly, as long as it has no comments, blank
+/// lines or records without a sequence (which Read skips), and ends each
+/// line (including the last) with a single '\n'.
+type Writer struct {
+	w *bufio.Writer
+
+	/// Width is the maximum length of a sequence line, or Unwrapped. Writes
+	/// fail with a LineWidthError if it is negative.
+	Width int
+
+	/// Uppercase converts sequences to uppercase as they are written,
+	/// discarding any soft-masking
+	Uppercase bool
+}
+
+/// NewWriter creates a Writer that wraps sequences at the given width.
+/// Panics if width is negative.
+func NewWriter(w io.Writer, width int) *Writer {
+	if width < 0 {
+		panic(fmt.Sprintf("fasta: negative line width %d", width))
+	}
+	return &Writer{
+		w:     bufio.NewWriter(w),
+		Width: width,
+	}
+}
+
+/// Write writes a record as read by Read, using its Name as the whole header
+/// line. Records carrying an error are not written; the error is returned
+/// instead. As with WriteRecord, the name must not be empty.
+func (self *Writer) Write(s String) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if s.Name == "" || strings.ContainsAny(s.Name, "\r\n") {
+		return InvalidHeaderError(s.Name)
+	}
+	return self.write(s.Name, s.Sequence)
+}
+
+/// WriteRecord writes a record with a header made of its name and, if it's
+/// not empty, a space and its description.
+func (self *Writer) WriteRecord(name, description, sequence string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return InvalidHeaderError(name)
+	}
+	if strings.ContainsAny(description, "\r\n") {
+		return InvalidHeaderError(description)
+	}
+
+	header := name
+	if description != "" {
+		header += " " + description
+	}
+	return self.write(header, sequence)
+}
+
+/// Flush writes any buffered records out to the underlying writer
+func (self *Writer) Flush() error {
+	return self.w.Flush()
+}
+
+func (self *Writer) write(header, sequence string) error {
+	if self.Width < 0 {
+		return LineWidthError(self.Width)
+	}
+	if self.Uppercase {
+		sequence = strings.ToUpper(sequence)
+	}
+
+	// bufio.Writer remembers the first error, so it's enough to check the
+	// last write of each line
+	self.w.WriteByte('>')
+	self.w.WriteString(header)
+	if err := self.w.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	width := self.Width
+	if width == Unwrapped {
+		width = len(sequence)
+	}
+	for len(sequence) > 0 {
+		n := min(width, len(sequence))
+		self.w.WriteString(sequence[:n])
+		sequence = sequence[n:]
+		if err := self.w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/// Id returns the record's identifier: its name up to the first whitespace
+func (self String) Id() string {
+	if i := strings.IndexAny(self.Name, " \t"); i >= 0 {
+		return self.Name[:i]
+	}
+	return self.Name
+}
+
+/// Description returns the rest of the record's name after its identifier,
+/// if any
+func (self String) Description() string {
+	if i := strings.IndexAny(self.Name, " \t"); i >= 0 {
+		return strings.TrimLeft(self.Name[i:], " \t")
+	}
+	return ""
+}
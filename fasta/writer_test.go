@@ -0,0 +1,155 @@
+package fasta
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_WriterWrapsSequences(t *testing.T) {
+	tests := []struct {
+		width    int
+		expected string
+	}{
+		{Unwrapped, ">seq\nGATTACAGATTACA\n"},
+		{5, ">seq\nGATTA\nCAGAT\nTACA\n"},
+		{7, ">seq\nGATTACA\nGATTACA\n"},
+		{Width60, ">seq\nGATTACAGATTACA\n"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, test.width)
+		if err := w.Write(String{"seq", "GATTACAGATTACA", nil}); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if buf.String() != test.expected {
+			t.Errorf("Width %d: expected %q, got %q", test.width, test.expected, buf.String())
+		}
+	}
+}
+
+func Test_WriterBuffersUntilFlushed(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Width60)
+	w.Write(String{"seq", "GATTACA", nil})
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing before flushing, got %q", buf.String())
+	}
+	w.Flush()
+	if buf.String() != ">seq\nGATTACA\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func Test_WriterCanUppercase(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Unwrapped)
+	w.Uppercase = true
+	w.Write(String{"seq", "gatTACa", nil})
+	w.Flush()
+	if buf.String() != ">seq\nGATTACA\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func Test_WriteRecordBuildsHeader(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Width80)
+	w.WriteRecord("seq1", "a description", "GATTACA")
+	w.WriteRecord("seq2", "", "")
+	w.Flush()
+	if buf.String() != ">seq1 a description\nGATTACA\n>seq2\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func Test_WriterRejectsBadRecords(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, Width60)
+	bad := []error{
+		w.Write(String{"two\nlines", "GATTACA", nil}),
+		w.Write(String{"", "GATTACA", nil}),
+		w.WriteRecord("has space", "", "GATTACA"),
+		w.WriteRecord("", "", "GATTACA"),
+		w.WriteRecord("seq", "two\nlines", "GATTACA"),
+	}
+	for i, err := range bad {
+		if _, ok := err.(InvalidHeaderError); !ok {
+			t.Errorf("%d: expected InvalidHeaderError, got %v", i, err)
+		}
+	}
+
+	w.Width = -1
+	if err := w.Write(String{"seq", "GATTACA", nil}); err != LineWidthError(-1) {
+		t.Errorf("Expected LineWidthError, got %v", err)
+	}
+
+	readErr := errors.New("read failed")
+	if err := w.Write(String{"", "", readErr}); err != readErr {
+		t.Errorf("Expected the record's error, got %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (self failingWriter) Write(buf []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func Test_WriterReportsWriteErrors(t *testing.T) {
+	w := NewWriter(failingWriter{}, Width60)
+	w.Write(String{"seq", "GATTACA", nil})
+	if err := w.Flush(); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func Test_ReadThenWriteReproducesInput(t *testing.T) {
+	inputs := []struct {
+		text  string
+		width int
+	}{
+		{">Rosalind_1 first sequence\nGATTACAGAT\nTACA\n>Rosalind_2\nCCCCGGGGAA\nTTTT\n", 10},
+		{">one\nGATTACA\n>two\ttabbed\nacgtACGT\n", Unwrapped},
+		{"", Width60},
+		{">chr1\n" + strings.Repeat("GATTACA", 15000) + "\n>chr2\nACGT\n", Unwrapped},
+	}
+
+	for _, input := range inputs {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, input.width)
+		for s := range Read(strings.NewReader(input.text)) {
+			if err := w.Write(s); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+		}
+		w.Flush()
+		if buf.String() != input.text {
+			t.Errorf("Expected %d bytes of %.40q..., got %d bytes of %.40q...",
+				len(input.text), input.text, buf.Len(), buf.String())
+		}
+	}
+}
+
+func Test_IdAndDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		description string
+	}{
+		{"Rosalind_1", "Rosalind_1", ""},
+		{"chr1 Homo sapiens", "chr1", "Homo sapiens"},
+		{"chr2\t  spaced", "chr2", "spaced"},
+	}
+	for _, test := range tests {
+		s := String{test.name, "", nil}
+		if s.Id() != test.id || s.Description() != test.description {
+			t.Errorf("Expected %q and %q from %q, got %q and %q",
+				test.id, test.description, test.name, s.Id(), s.Description())
+		}
+	}
+}
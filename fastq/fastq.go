@@ -0,0 +1,285 @@
+package fastq
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/tcsc/rosalind/fasta"
+)
+
+/// Record is a single FASTQ read. It embeds a fasta.String, so the name,
+/// sequence and error can be used exactly as for a FASTA record, and the
+/// String field passed to anything expecting one.
+type Record struct {
+	fasta.String
+
+	/// Quality holds the decoded Phred score of each base
+	Quality []byte
+}
+
+/// Encoding describes how quality scores are written as text
+type Encoding int
+
+const (
+	/// AutoDetect works out the encoding from the quality scores themselves.
+	/// It is only valid when reading.
+	AutoDetect Encoding = iota
+
+	/// Phred33 offsets each score by 33 ('!'), as used by Sanger and modern
+	/// Illumina machines
+	Phred33
+
+	/// Phred64 offsets each score by 64 ('@'), as used by Illumina 1.3-1.7
+	Phred64
+)
+
+/// detectionLimit is the number of records the reader will buffer while
+/// trying to detect the quality encoding, before assuming Phred33
+const detectionLimit = 1000
+
+/// maxLineLength is the longest line the reader will accept. Long reads are
+/// often written on a single line, so this is far beyond bufio's default.
+const maxLineLength = 1 << 30
+
+/// FormatError describes a malformed FASTQ record. Record numbers start
+/// at 1.
+type FormatError struct {
+	Record int
+	Msg    string
+}
+
+func (self FormatError) Error() string {
+	return fmt.Sprintf("FASTQ record %d: %s", self.Record, self.Msg)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Offset returns the character value that encodes a score of zero
+func (self Encoding) Offset() byte {
+	if self == Phred64 {
+		return '@'
+	}
+	return '!'
+}
+
+func (self Encoding) String() string {
+	switch self {
+	case Phred33:
+		return "Phred+33"
+	case Phred64:
+		return "Phred+64"
+	}
+	return "auto"
+}
+
+/// Decode converts a string of encoded quality scores into Phred scores
+func (self Encoding) Decode(text string) ([]byte, error) {
+	offset := self.Offset()
+	result := make([]byte, len(text))
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c < offset || c > '~' {
+			return nil, fmt.Errorf("invalid %s quality %q at %d", self, c, i)
+		}
+		result[i] = c - offset
+	}
+	return result, nil
+}
+
+/// Encode converts Phred scores into text. Scores too high to encode are
+/// capped at the highest that can be.
+func (self Encoding) Encode(quality []byte) string {
+	offset := self.Offset()
+	result := make([]byte, len(quality))
+	for i, q := range quality {
+		result[i] = min(q, '~'-offset) + offset
+	}
+	return string(result)
+}
+
+/// detectEncoding guesses the encoding of some quality text, returning
+/// AutoDetect if it could be either
+func detectEncoding(text string) Encoding {
+	result := AutoDetect
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c < '@':
+			return Phred33
+		case c > 'K':
+			// higher than Phred+33 quality scores go in practice
+			result = Phred64
+		}
+	}
+	return result
+}
+
+/// ReadFile reads FASTQ records from the named file, as per Read
+func ReadFile(filename string, encoding Encoding) <-chan Record {
+	file, err := fasta.Open(filename)
+	if err != nil {
+		ch := make(chan Record, 1)
+		ch <- Record{String: fasta.String{Error: err}}
+		close(ch)
+		return ch
+	}
+	return Read(file, encoding)
+}
+
+/// Read streams FASTQ records from a reader over a channel, closing the
+/// reader when done if it is an io.Closer. The input may be gzip or BGZF
+/// compressed, and records may have their sequence and quality split over
+/// several lines. Any error is delivered as the last record, with its Error
+/// set. The encoding is detected as per NewScanner. The channel must be
+/// drained; a consumer that might stop early should use ReadContext and
+/// cancel it, or a Scanner.
+func Read(reader io.Reader, encoding Encoding) <-chan Record {
+	return ReadContext(context.Background(), reader, encoding)
+}
+
+/// ReadContext is Read, but gives up (closing the channel and reader) once
+/// ctx is done.
+func ReadContext(ctx context.Context, reader io.Reader, encoding Encoding) <-chan Record {
+	ch := make(chan Record, 2)
+	go func() {
+		defer closeReader(reader)
+		defer close(ch)
+		for record := range Records(ctx, reader, encoding) {
+			select {
+			case ch <- record:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+/// closeReader closes the input stream if it's closeable
+func closeReader(reader io.Reader) {
+	if closer, ok := reader.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+/// rawRecord is a record whose quality hasn't been decoded yet
+type rawRecord struct {
+	number   int
+	name     string
+	sequence string
+	quality  string
+}
+
+/// parser splits FASTQ text into records
+type parser struct {
+	s      *bufio.Scanner
+	number int
+}
+
+func newParser(reader io.Reader) *parser {
+	s := bufio.NewScanner(reader)
+	s.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+	return &parser{s: s}
+}
+
+/// next reads the next record, returning io.EOF at the end of the input
+func (self *parser) next() (rawRecord, error) {
+	var header string
+	for {
+		if !self.s.Scan() {
+			if err := self.s.Err(); err != nil {
+				return rawRecord{}, FormatError{self.number + 1, err.Error()}
+			}
+			return rawRecord{}, io.EOF
+		}
+		header = strings.TrimSpace(self.s.Text())
+		if header != "" {
+			break
+		}
+	}
+
+	self.number++
+	result := rawRecord{number: self.number}
+	fail := func(msg string) (rawRecord, error) {
+		return rawRecord{}, FormatError{self.number, msg}
+	}
+	failScan := func(msg string) (rawRecord, error) {
+		if err := self.s.Err(); err != nil {
+			return fail(err.Error())
+		}
+		return fail(msg)
+	}
+
+	if header[0] != '@' {
+		return fail(fmt.Sprintf("expected '@', got %q", header))
+	}
+	result.name = header[1:]
+
+	// the sequence runs until the '+' separator line
+	var seq strings.Builder
+	for {
+		if !self.s.Scan() {
+			return failScan("missing '+' line")
+		}
+		line := strings.TrimSpace(self.s.Text())
+		if strings.HasPrefix(line, "+") {
+			if name := line[1:]; name != "" && name != result.name {
+				return fail(fmt.Sprintf("'+' line names %q, not %q", name, result.name))
+			}
+			break
+		}
+		seq.WriteString(line)
+	}
+	result.sequence = seq.String()
+
+	// the quality can start with '@', so the only way to tell where it ends
+	// is by its length
+	var quality strings.Builder
+	for quality.Len() < len(result.sequence) {
+		if !self.s.Scan() {
+			if err := self.s.Err(); err != nil {
+				return fail(err.Error())
+			}
+			break
+		}
+		quality.WriteString(strings.TrimSpace(self.s.Text()))
+	}
+	result.quality = quality.String()
+
+	if len(result.quality) != len(result.sequence) {
+		return fail(fmt.Sprintf("sequence has %d bases but quality has %d",
+			len(result.sequence), len(result.quality)))
+	}
+	return result, nil
+}
+
+/// ReadSequences streams records from either FASTA or FASTQ text over a
+/// channel, as per Sequences, closing the reader when done if it is an
+/// io.Closer. This lets commands accept either format, compressed or not.
+/// The channel must be drained; a consumer that might stop early should use
+/// ReadSequencesContext and cancel it, or Sequences.
+func ReadSequences(reader io.Reader) <-chan fasta.String {
+	return ReadSequencesContext(context.Background(), reader)
+}
+
+/// ReadSequencesContext is ReadSequences, but gives up (closing the channel
+/// and reader) once ctx is done.
+func ReadSequencesContext(ctx context.Context, reader io.Reader) <-chan fasta.String {
+	ch := make(chan fasta.String, 2)
+	go func() {
+		defer closeReader(reader)
+		defer close(ch)
+		for s := range Sequences(ctx, reader) {
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
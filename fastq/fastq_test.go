@@ -0,0 +1,263 @@
+package fastq
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/tcsc/rosalind/fasta"
+)
+
+func readAll(t *testing.T, text string, encoding Encoding) ([]Record, error) {
+	records := []Record{}
+	for r := range Read(strings.NewReader(text), encoding) {
+		if r.Error != nil {
+			return records, r.Error
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func Test_ReadFourLineRecords(t *testing.T) {
+	text := "@read1 first\nGATTACA\n+\n!!5?III\n@read2\nACGT\n+read2\nIIII\n"
+	records, err := readAll(t, text, Phred33)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	if records[0].Name != "read1 first" || records[0].Sequence != "GATTACA" {
+		t.Errorf("Unexpected record %#v", records[0])
+	}
+	expected := []byte{0, 0, 20, 30, 40, 40, 40}
+	if !reflect.DeepEqual(records[0].Quality, expected) {
+		t.Errorf("Expected quality %v, got %v", expected, records[0].Quality)
+	}
+	if records[1].String != (fasta.String{Name: "read2", Sequence: "ACGT"}) {
+		t.Errorf("Unexpected record %#v", records[1].String)
+	}
+}
+
+func Test_ReadMultiLineRecords(t *testing.T) {
+	// the second quality line starts with '@', which mustn't be mistaken
+	// for the next header
+	text := "@read1\nGATT\nACA\n+\n!!5?\n@II\n@read2\nAC\n+\nII\n"
+	records, err := readAll(t, text, Phred33)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(records) != 2 || records[0].Sequence != "GATTACA" || records[1].Name != "read2" {
+		t.Fatalf("Unexpected records %#v", records)
+	}
+	if records[0].Quality[4] != 31 {
+		t.Errorf("Expected '@' to decode as 31, got %d", records[0].Quality[4])
+	}
+}
+
+func Test_ReadPhred64(t *testing.T) {
+	records, err := readAll(t, "@read\nACGT\n+\n@Jhh\n", Phred64)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	expected := []byte{0, 10, 40, 40}
+	if !reflect.DeepEqual(records[0].Quality, expected) {
+		t.Errorf("Expected quality %v, got %v", expected, records[0].Quality)
+	}
+
+	if _, err := readAll(t, "@read\nACGT\n+\n!!!!\n", Phred64); err == nil {
+		t.Error("Expected an error decoding Phred+33 as Phred+64")
+	}
+}
+
+func Test_ReadAutoDetectsEncoding(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected byte
+	}{
+		// undecided until the second record
+		{"@a\nAC\n+\nII\n@b\nAC\n+\n!!\n", 40},
+		{"@a\nAC\n+\nII\n@b\nAC\n+\nhh\n", 9},
+		// never decided, so assume Phred+33
+		{"@a\nAC\n+\nII\n", 40},
+	}
+	for _, test := range tests {
+		records, err := readAll(t, test.text, AutoDetect)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if records[0].Quality[0] != test.expected {
+			t.Errorf("Expected first quality %d for %q, got %d",
+				test.expected, test.text, records[0].Quality[0])
+		}
+	}
+}
+
+func Test_ReadErrorsCarryRecordNumber(t *testing.T) {
+	tests := []struct {
+		text   string
+		record int
+	}{
+		{">a\nAC\n+\nII\n", 1},
+		{"@a\nAC\n+\nII\n@b\nACG\n+\nII\n", 2},
+		{"@a\nAC\n+\nII\n@b\nACG\n+\nIIII\n", 2},
+		{"@a\nAC\n+\nII\n\n@b\nAC\n", 2},
+		{"@a\nAC\n+b\nII\n", 1},
+		{"@a\nAC\n+\nII\n@b\nAC\n+\n\x01\x01\n", 2},
+	}
+	for _, test := range tests {
+		records, err := readAll(t, test.text, Phred33)
+		e, ok := err.(FormatError)
+		if !ok {
+			t.Errorf("Expected FormatError for %q, got %v", test.text, err)
+		} else if e.Record != test.record {
+			t.Errorf("Expected error in record %d for %q, got %d", test.record, test.text, e.Record)
+		}
+		if len(records) != test.record-1 {
+			t.Errorf("Expected %d good records for %q, got %d", test.record-1, test.text, len(records))
+		}
+	}
+}
+
+func Test_ReadAcceptsLongSingleLineReads(t *testing.T) {
+	seq := strings.Repeat("ACGT", 25000)
+	text := "@long\n" + seq + "\n+\n" + strings.Repeat("I", len(seq)) + "\n"
+	records, err := readAll(t, text, AutoDetect)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if len(records) != 1 || records[0].Sequence != seq || len(records[0].Quality) != len(seq) {
+		t.Errorf("Expected one %d base read", len(seq))
+	}
+}
+
+func Test_ReadReportsInputErrorsWithRecordNumber(t *testing.T) {
+	failure := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("@a\nAC\n+\nII\n@b\nAC"), iotest.ErrReader(failure))
+
+	var last Record
+	for last = range Read(r, Phred33) {
+	}
+	if last.Error != (FormatError{2, failure.Error()}) {
+		t.Errorf("Expected the read error in record 2, got %v", last.Error)
+	}
+}
+
+func Test_ReadNonExistentFileProducesError(t *testing.T) {
+	count := 0
+	for r := range ReadFile("/no/such/file", Phred33) {
+		count++
+		if r.Error == nil {
+			t.Fatal("Expected an error")
+		}
+	}
+	if count != 1 {
+		t.Fatalf("Expected one record, got %d", count)
+	}
+}
+
+func Test_WriterRoundTrips(t *testing.T) {
+	text := "@read1 first\nGATTACA\n+\n!!5?III\n@read2\nACGT\n+\nIIII\n"
+	for _, encoding := range []Encoding{Phred33, Phred64} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, encoding)
+		for r := range Read(strings.NewReader(text), Phred33) {
+			if err := w.Write(r); err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+		}
+		w.Flush()
+
+		if encoding == Phred33 && buf.String() != text {
+			t.Errorf("Expected %q, got %q", text, buf.String())
+		}
+
+		records, err := readAll(t, buf.String(), encoding)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if len(records) != 2 || records[0].Quality[2] != 20 {
+			t.Errorf("Unexpected records after writing as %s: %#v", encoding, records)
+		}
+	}
+}
+
+func Test_WriterRejectsMismatchedQuality(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, Phred33)
+	w.Write(Record{fasta.String{Name: "a", Sequence: "AC"}, []byte{1, 2}})
+	err := w.Write(Record{fasta.String{Name: "b", Sequence: "ACG"}, []byte{1, 2}})
+	if e, ok := err.(FormatError); !ok || e.Record != 2 {
+		t.Errorf("Expected FormatError in record 2, got %v", err)
+	}
+}
+
+func Test_EncodeCapsHighScores(t *testing.T) {
+	if s := Phred64.Encode([]byte{0, 62, 100}); s != "@~~" {
+		t.Errorf("Expected \"@~~\", got %q", s)
+	}
+}
+
+func Test_ReadSequencesDecompressesStreams(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte("@a\nACGT\n+\nIIII\n"))
+	w.Close()
+
+	records := []fasta.String{}
+	for s := range ReadSequences(&buf) {
+		records = append(records, s)
+	}
+	expected := []fasta.String{{Name: "a", Sequence: "ACGT"}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected %v, got %v", expected, records)
+	}
+}
+
+func Test_ReadSequencesAcceptsEitherFormat(t *testing.T) {
+	inputs := []string{
+		">read1\nGATT\nACA\n>read2\nACGT\n",
+		"\n@read1\nGATTACA\n+\nIIIIIII\n@read2\nACGT\n+\n!!!!\n",
+	}
+	expected := []fasta.String{
+		{Name: "read1", Sequence: "GATTACA"},
+		{Name: "read2", Sequence: "ACGT"},
+	}
+	for _, input := range inputs {
+		actual := []fasta.String{}
+		for s := range ReadSequences(strings.NewReader(input)) {
+			actual = append(actual, s)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v from %q, got %v", expected, input, actual)
+		}
+	}
+}
+
+type closeSignaller struct {
+	io.Reader
+	closed chan bool
+}
+
+func (self closeSignaller) Close() error {
+	self.closed <- true
+	return nil
+}
+
+func Test_ReadSequencesClosesInput(t *testing.T) {
+	closed := make(chan bool, 1)
+	for _ = range ReadSequences(closeSignaller{strings.NewReader("@a\nA\n+\nI\n"), closed}) {
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for close")
+	}
+}
@@ -0,0 +1,188 @@
+package fastq
+
+import (
+	"context"
+	"io"
+	"iter"
+
+	"github.com/tcsc/rosalind/fasta"
+)
+
+/// Scanner reads FASTQ records one at a time, on the caller's goroutine, in
+/// the same way as a fasta.Scanner. Gzip-compressed input is decompressed
+/// transparently.
+type Scanner struct {
+	ctx      context.Context
+	p        *parser
+	encoding Encoding
+	record   Record
+	err      error
+
+	// pending holds records parsed while detecting the encoding, and
+	// deferred any error that ended the input before they were returned
+	pending  []rawRecord
+	deferred error
+	done     bool
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// NewScanner creates a Scanner reading from r. If the encoding is
+/// AutoDetect, records are read ahead until one is found whose qualities
+/// could only be in one encoding; if none is found, Phred33 is assumed. The
+/// Scanner doesn't close r.
+func NewScanner(r io.Reader, encoding Encoding) *Scanner {
+	return NewScannerContext(context.Background(), r, encoding)
+}
+
+/// NewScannerContext creates a Scanner that stops, with the context's error,
+/// once ctx is done.
+func NewScannerContext(ctx context.Context, r io.Reader, encoding Encoding) *Scanner {
+	result := &Scanner{ctx: ctx, encoding: encoding}
+	if input, err := fasta.Decompress(r); err != nil {
+		result.err = err
+	} else {
+		result.p = newParser(input)
+	}
+	return result
+}
+
+/// Next advances to the next record, returning false at the end of the
+/// input or on an error.
+func (self *Scanner) Next() bool {
+	if self.err != nil {
+		return false
+	}
+	if err := self.ctx.Err(); err != nil {
+		self.err = err
+		return false
+	}
+
+	if self.encoding == AutoDetect {
+		self.detectEncoding()
+		if self.err != nil {
+			return false
+		}
+	}
+
+	var raw rawRecord
+	if len(self.pending) > 0 {
+		raw, self.pending = self.pending[0], self.pending[1:]
+	} else if self.done {
+		self.err = self.deferred
+		return false
+	} else {
+		var err error
+		if raw, err = self.p.next(); err != nil {
+			self.done = true
+			if err != io.EOF {
+				self.err = err
+			}
+			return false
+		}
+	}
+
+	quality, err := self.encoding.Decode(raw.quality)
+	if err != nil {
+		self.err = FormatError{raw.number, err.Error()}
+		return false
+	}
+	self.record = Record{String: fasta.String{Name: raw.name, Sequence: raw.sequence}, Quality: quality}
+	return true
+}
+
+/// detectEncoding reads ahead until the encoding is known, the detection
+/// limit is reached or the input runs out
+func (self *Scanner) detectEncoding() {
+	for self.encoding == AutoDetect && !self.done && len(self.pending) < detectionLimit {
+		if err := self.ctx.Err(); err != nil {
+			self.err = err
+			return
+		}
+
+		raw, err := self.p.next()
+		if err != nil {
+			// anything successfully parsed before the error still counts
+			self.done = true
+			if err != io.EOF {
+				self.deferred = err
+			}
+			break
+		}
+		self.pending = append(self.pending, raw)
+		self.encoding = detectEncoding(raw.quality)
+	}
+
+	if self.encoding == AutoDetect {
+		self.encoding = Phred33
+	}
+}
+
+/// Record returns the record found by the last call to Next
+func (self *Scanner) Record() Record {
+	return self.record
+}
+
+/// Err returns the error, if any, that stopped the Scanner
+func (self *Scanner) Err() error {
+	return self.err
+}
+
+/// Records iterates over the records in r, reading them synchronously as
+/// the loop asks for them, so it's safe to break out early. An error ends
+/// the iteration, delivered both as the error and in the Record's Error
+/// field.
+func Records(ctx context.Context, r io.Reader, encoding Encoding) iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		s := NewScannerContext(ctx, r, encoding)
+		for s.Next() {
+			if !yield(s.Record(), nil) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(Record{String: fasta.String{Error: err}}, err)
+		}
+	}
+}
+
+/// Sequences iterates over the records in either FASTA or FASTQ text,
+/// depending on how it starts, discarding any quality scores. Like Records,
+/// it reads synchronously.
+func Sequences(ctx context.Context, r io.Reader) iter.Seq2[fasta.String, error] {
+	return func(yield func(fasta.String, error) bool) {
+		input, err := fasta.Decompress(r)
+		if err != nil {
+			yield(fasta.String{Error: err}, err)
+			return
+		}
+
+		// skip any leading whitespace to find the first real character
+		for {
+			c, _, err := input.ReadRune()
+			if err != nil || (c != ' ' && c != '\t' && c != '\r' && c != '\n') {
+				if err == nil {
+					input.UnreadRune()
+				}
+				break
+			}
+		}
+
+		if first, _ := input.Peek(1); len(first) == 0 || first[0] != '@' {
+			for s, err := range fasta.Records(ctx, input) {
+				if !yield(s, err) {
+					return
+				}
+			}
+			return
+		}
+
+		for record, err := range Records(ctx, input, AutoDetect) {
+			if !yield(record.String, err) {
+				return
+			}
+		}
+	}
+}
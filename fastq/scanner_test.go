@@ -0,0 +1,98 @@
+package fastq
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ScannerMatchesChannelReader(t *testing.T) {
+	tests := []string{
+		"",
+		"@a\nAC\n+\nII\n@b\nAC\n+\nhh\n",
+		"@a\nGAT\nTACA\n+\nIII\nIIII\n",
+	}
+	for _, text := range tests {
+		expected, err := readAll(t, text, AutoDetect)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+
+		actual := []Record{}
+		s := NewScanner(strings.NewReader(text), AutoDetect)
+		for s.Next() {
+			actual = append(actual, s.Record())
+		}
+		if s.Err() != nil {
+			t.Fatalf("Unexpected error: %s", s.Err().Error())
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v for %q, got %v", expected, text, actual)
+		}
+	}
+}
+
+func Test_ScannerReturnsRecordsReadWhileDetecting(t *testing.T) {
+	// the error in the second record ends detection, but the first record
+	// still comes out before it
+	s := NewScanner(strings.NewReader("@a\nAC\n+\nII\n@b\nACG\n+\nII\n"), AutoDetect)
+	if !s.Next() || s.Record().Name != "a" {
+		t.Fatalf("Expected record a before the error, got %v", s.Err())
+	}
+	if s.Next() {
+		t.Errorf("Unexpected record %v", s.Record())
+	}
+	if e, ok := s.Err().(FormatError); !ok || e.Record != 2 {
+		t.Errorf("Expected FormatError in record 2, got %v", s.Err())
+	}
+}
+
+func Test_ScannerStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := NewScannerContext(ctx, strings.NewReader("@a\nA\n+\nI\n@b\nA\n+\nI\n"), Phred33)
+	if !s.Next() {
+		t.Fatalf("Expected a record")
+	}
+	cancel()
+	if s.Next() {
+		t.Errorf("Expected cancellation to stop the scanner")
+	}
+	if s.Err() != context.Canceled {
+		t.Errorf("Expected %v, got %v", context.Canceled, s.Err())
+	}
+}
+
+func Test_SequencesCanBeAbandoned(t *testing.T) {
+	for _, text := range []string{"@a\nA\n+\nI\n@b\nA\n+\nI\n", ">a\nA\n>b\nC\n"} {
+		names := []string{}
+		for s, err := range Sequences(context.Background(), strings.NewReader(text)) {
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err.Error())
+			}
+			names = append(names, s.Name)
+			break
+		}
+		if !reflect.DeepEqual(names, []string{"a"}) {
+			t.Errorf("Expected just a from %q, got %v", text, names)
+		}
+	}
+}
+
+func Test_CancellingReadSequencesContextClosesInput(t *testing.T) {
+	// enough records to fill the channel's buffer
+	text := strings.Repeat("@a\nACGT\n+\nIIII\n", 100)
+
+	closed := make(chan bool, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	records := ReadSequencesContext(ctx, closeSignaller{strings.NewReader(text), closed})
+	<-records
+	cancel()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for close")
+	}
+}
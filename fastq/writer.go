@@ -0,0 +1,59 @@
+package fastq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/// Writer writes FASTQ records in the common four line form, with unwrapped
+/// sequences and qualities and a bare '+' separator. Output is buffered, so
+/// Flush must be called once all the records are written.
+type Writer struct {
+	w        *bufio.Writer
+	encoding Encoding
+	count    int
+}
+
+/// NewWriter creates a Writer that encodes qualities with the given encoding.
+/// Panics if the encoding is AutoDetect.
+func NewWriter(w io.Writer, encoding Encoding) *Writer {
+	if encoding == AutoDetect {
+		panic("fastq: can't write with an auto-detected encoding")
+	}
+	return &Writer{
+		w:        bufio.NewWriter(w),
+		encoding: encoding,
+	}
+}
+
+/// Write writes a single record. Records carrying an error are not written;
+/// the error is returned instead.
+func (self *Writer) Write(record Record) error {
+	if record.Error != nil {
+		return record.Error
+	}
+
+	self.count++
+	if strings.ContainsAny(record.Name, "\r\n") {
+		return FormatError{self.count, fmt.Sprintf("invalid name %q", record.Name)}
+	}
+	if len(record.Quality) != len(record.Sequence) {
+		return FormatError{self.count, fmt.Sprintf("sequence has %d bases but quality has %d",
+			len(record.Sequence), len(record.Quality))}
+	}
+
+	self.w.WriteByte('@')
+	self.w.WriteString(record.Name)
+	self.w.WriteByte('\n')
+	self.w.WriteString(record.Sequence)
+	self.w.WriteString("\n+\n")
+	self.w.WriteString(self.encoding.Encode(record.Quality))
+	return self.w.WriteByte('\n')
+}
+
+/// Flush writes any buffered records out to the underlying writer
+func (self *Writer) Flush() error {
+	return self.w.Flush()
+}
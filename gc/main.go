@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/tcsc/rosalind/fasta"
+	"github.com/tcsc/rosalind/fastq"
 	"os"
 )
 
@@ -19,7 +20,13 @@ func gcContent(s string) float64 {
 func main() {
 	gcMax := 0.0
 	leader := ""
-	for str := range fasta.ReadFile(os.Args[1]) {
+	file, err := fasta.Open(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+
+	// fastq.ReadSequences takes care of closing the file
+	for str := range fastq.ReadSequences(file) {
 		if str.Error != nil {
 			panic(str.Error)
 		}
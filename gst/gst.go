@@ -260,19 +260,10 @@ func (self *SuffixTree) index(index int) { //, index int) {
 					// nope - we need to split the active node at the insertion
 					// point so we can insert a new node that encodes our active
 					// suffix
-
-					fmt.Printf("\nSplitting (%#v): \"%s\"\n",
-						activeChild.str,
-						self.nodeString(activeChild))
-
-					gch := self.split(activeChild, active.length)
+					self.split(activeChild, active.length)
 					newChild := newNode(index, i)
 					activeChild.children[c] = newChild
 
-					fmt.Printf("prefix    %#v: \"%s\"\n", activeChild.str, self.nodeString(activeChild))
-					fmt.Printf("suffix    %#v: \"%s\"\n", gch.str, self.nodeString(gch))
-					fmt.Printf("new child %#v: \"%s\"\n", newChild.str, self.nodeString(newChild))
-
 					prevNode = link(prevNode, activeChild)
 				}
 			}
@@ -303,11 +294,9 @@ func (self *SuffixTree) find(s string) (*node, int) {
 	node := self.root
 	nodeStr := ""
 	index := 0
-	offs := 0
 	for _, ch := range s {
 		if len(nodeStr) == 0 {
 			if n, ok := node.children[ch]; !ok {
-				fmt.Printf("Missing child node at offset %d\n", offs)
 				return nil, 0
 			} else {
 				node = n
@@ -317,11 +306,9 @@ func (self *SuffixTree) find(s string) (*node, int) {
 		}
 		otherChar, size := utf8.DecodeRuneInString(nodeStr)
 		if ch != otherChar {
-			fmt.Printf("Bad char at offset %d (expected %c, got %c)\n", offs, ch, otherChar)
 			return nil, 0
 		}
 		index += size
-		offs += size
 		nodeStr = nodeStr[size:]
 	}
 	return node, index
@@ -382,7 +369,7 @@ func (self *SuffixTree) FindAll(s string) []StringLoc {
 	// obvious potential speedup: fork off many goroutines to walk
 	// each descendant in parallel and return them to the caller.
 
-	q := []point{point{n: n, length: n.str.length - offset}}
+	q := []point{point{n: n, length: self.nodeLen(n) - offset}}
 	var pt point
 	for len(q) > 0 {
 		pt, q = q[len(q)-1], q[:len(q)-1]
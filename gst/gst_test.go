@@ -129,6 +129,17 @@ func Test_FindAllActuallyFindsAll(t *testing.T) {
 	}
 }
 
+func Test_FindAllFindsMatchesEndingInLeaves(t *testing.T) {
+	tree := New("AAGGTTGGCCATCGG")
+	tests := map[string]int{"GGCCA": 6, "TCGG": 11, "AAGGTTGGCCATCGG": 0}
+	for pattern, expected := range tests {
+		points := tree.FindAll(pattern)
+		if len(points) != 1 || points[0].Offset != expected {
+			t.Errorf("Expected %q at %d, got %v", pattern, expected, points)
+		}
+	}
+}
+
 func Test_GetStringReturnsOriginalString(t *testing.T) {
 	strings := []string{
 		"The answer ... is fourty-two!",
@@ -3,10 +3,25 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"github.com/tcsc/rosalind/basestring"
 	"os"
 	"strings"
 )
 
+/// maxLineLength is the longest input line accepted; whole genomes may be
+/// given on a single line
+const maxLineLength = 1 << 30
+
+/// isDNASymbol picks out the bytes basestring stores as DNA without
+/// soft-masking
+var isDNASymbol [256]bool
+
+func init() {
+	for _, c := range basestring.DNA.Symbols() {
+		isDNASymbol[c] = true
+	}
+}
+
 func main() {
 	file, err := os.Open(os.Args[1])
 	if err != nil {
@@ -16,9 +31,13 @@ func main() {
 
 	ss := []string{}
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
 	for scanner.Scan() {
 		ss = append(ss, strings.TrimSpace(scanner.Text()))
 	}
+	if err := scanner.Err(); err != nil {
+		panic(err)
+	}
 
 	if len(ss) < 2 {
 		panic("Expected at least 2 strings")
@@ -27,6 +46,9 @@ func main() {
 	fmt.Printf("%d\n", HammingDistance(ss[0], ss[1]))
 }
 
+/// HammingDistance counts the positions at which two equal-length strings
+/// differ, comparing bytes exactly. Strings of plain uppercase bases take the
+/// packed, word-parallel path; anything else is compared byte by byte.
 func HammingDistance(a, b string) int {
 	if len(a) != len(b) {
 		panic(fmt.Sprintf("string length mismatch, a: %d, b: %d\n",
@@ -34,12 +56,32 @@ func HammingDistance(a, b string) int {
 			len(b)))
 	}
 
+	if isPlainDNA(a) && isPlainDNA(b) {
+		sa, _ := basestring.FromString(a)
+		sb, _ := basestring.FromString(b)
+		dh, err := basestring.HammingDistance(sa, sb)
+		if err != nil {
+			panic(err)
+		}
+		return dh
+	}
+
 	dh := 0
 	for i := 0; i < len(a); i++ {
 		if a[i] != b[i] {
 			dh++
 		}
 	}
-
 	return dh
 }
+
+/// isPlainDNA checks that a string holds only uppercase bases and ambiguity
+/// codes, which basestring compares exactly as bytes would be.
+func isPlainDNA(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isDNASymbol[s[i]] {
+			return false
+		}
+	}
+	return true
+}
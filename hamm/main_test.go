@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_HammingDistanceComparesBytesExactly(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"GAGCCTACTAACGGGAT", "CATCGTAATGACGGCCT", 7},
+		{"GAGCNTACTA", "GAGCATACTA", 1},
+		{"gagccTACTA", "GAGCCTACTA", 5},
+		{"GAXC-", "GAYC-", 1},
+		{"", "", 0},
+	}
+	for _, test := range tests {
+		if actual := HammingDistance(test.a, test.b); actual != test.expected {
+			t.Errorf("Expected %d for %q vs %q, got %d", test.expected, test.a, test.b, actual)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/tcsc/rosalind/fasta"
+	"io"
 	"os"
+	"sort"
 	"time"
 )
 
@@ -65,12 +68,21 @@ func findLCS(a, b string) []string {
 }
 
 func main() {
-	fmt.Printf("Loading %s...\n", os.Args[1])
+	asFasta := flag.Bool("fasta", false, "Write just the substrings, as FASTA")
+	flag.Parse()
+
+	// keep progress messages out of the way of FASTA output
+	var log io.Writer = os.Stdout
+	if *asFasta {
+		log = os.Stderr
+	}
+
+	fmt.Fprintf(log, "Loading %s...\n", flag.Arg(0))
 
 	init := true
 	lcs := map[string]bool{}
 	start := time.Now()
-	for c := range fasta.ReadFile(os.Args[1]) {
+	for c := range fasta.ReadFile(flag.Arg(0)) {
 		if c.Error != nil {
 			panic(c.Error)
 		}
@@ -89,7 +101,27 @@ func main() {
 		}
 	}
 
-	fmt.Printf("Computation took %s\n", time.Since(start))
+	fmt.Fprintf(log, "Computation took %s\n", time.Since(start))
+
+	if *asFasta {
+		// number the records in a stable order, not the map's
+		sorted := []string{}
+		for s, _ := range lcs {
+			sorted = append(sorted, s)
+		}
+		sort.Strings(sorted)
+
+		w := fasta.NewWriter(os.Stdout, fasta.Width60)
+		for i, s := range sorted {
+			if err := w.WriteRecord(fmt.Sprintf("lcs_%d", i+1), "", s); err != nil {
+				panic(err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			panic(err)
+		}
+		return
+	}
 
 	for s, _ := range lcs {
 		fmt.Println(s)
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/tcsc/rosalind/codon"
+	"io/ioutil"
+	"strings"
+)
+
+type args struct {
+	filename string
+	modulus  int
+	code     int
+}
+
+func parseArgs() args {
+	result := args{}
+	flag.IntVar(&result.modulus, "m", 1000000, "Modulus for the count")
+	flag.IntVar(&result.code, "code", 1, "NCBI genetic code to use")
+	flag.Parse()
+
+	result.filename = flag.Arg(0)
+	return result
+}
+
+func main() {
+	args := parseArgs()
+
+	code, err := codon.Code(args.code)
+	if err != nil {
+		panic(err)
+	}
+
+	bytes, err := ioutil.ReadFile(args.filename)
+	if err != nil {
+		panic(err)
+	}
+
+	count, err := code.CountRNAsMod(strings.TrimSpace(string(bytes)), args.modulus)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%d\n", count)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/tcsc/rosalind/codon"
+	"github.com/tcsc/rosalind/fasta"
+	"github.com/tcsc/rosalind/fastq"
+	"io"
+	"os"
+)
+
+type args struct {
+	filename  string
+	code      int
+	minLength int
+	altStarts bool
+	distinct  bool
+}
+
+func parseArgs() args {
+	result := args{}
+	flag.IntVar(&result.code, "code", 1, "NCBI genetic code to translate with")
+	flag.IntVar(&result.minLength, "min", 0, "Shortest peptide to report")
+	flag.BoolVar(&result.altStarts, "alt-starts", false,
+		"Allow the genetic code's alternative start codons")
+	flag.BoolVar(&result.distinct, "distinct", false,
+		"Print each distinct peptide once, rather than every ORF")
+	flag.Parse()
+
+	result.filename = flag.Arg(0)
+	return result
+}
+
+func main() {
+	args := parseArgs()
+
+	code, err := codon.Code(args.code)
+	if err != nil {
+		panic(err)
+	}
+	opts := codon.ORFOptions{
+		Code:              code,
+		MinLength:         args.minLength,
+		AlternativeStarts: args.altStarts,
+	}
+
+	var input io.Reader = os.Stdin
+	if args.filename != "" {
+		file, err := fasta.Open(args.filename)
+		if err != nil {
+			panic(err)
+		}
+		input = file
+	}
+	records := fastq.ReadSequences(input)
+
+	seen := map[string]bool{}
+	for str := range records {
+		if str.Error != nil {
+			panic(str.Error)
+		}
+
+		orfs, err := codon.FindORFs(str.Sequence, opts)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, orf := range orfs {
+			if args.distinct {
+				if !seen[orf.Peptide] {
+					seen[orf.Peptide] = true
+					fmt.Println(orf.Peptide)
+				}
+			} else {
+				fmt.Printf("%s\t%s\n", str.Name, orf)
+			}
+		}
+	}
+}
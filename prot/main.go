@@ -5,6 +5,7 @@ import (
 	"github.com/tcsc/rosalind/codon"
 	"io/ioutil"
 	"os"
+	"strings"
 )
 
 func main() {
@@ -13,22 +14,10 @@ func main() {
 		panic(err)
 	}
 
-	fmt.Printf("%s\n", translate(string(bytes)))
-}
-
-func translate(s string) string {
-	n := 0
-	c := codon.New()
-	result := []rune{}
-	for _, x := range s {
-		c[n] = byte(x)
-		if n == 2 {
-			result = append(result, codon.Table[c])
-			n = 0
-		} else {
-			n = n + 1
-		}
+	protein, err := codon.Translate(strings.TrimSpace(string(bytes)), codon.Options{})
+	if err != nil {
+		panic(err)
 	}
 
-	return string(result)
+	fmt.Printf("%s\n", protein)
 }
@@ -0,0 +1,161 @@
+package protein
+
+import (
+	"fmt"
+	"math"
+)
+
+/// MassType selects which set of masses to use
+type MassType int
+
+const (
+	/// Monoisotopic masses are those of the most abundant isotope of each
+	/// element, as seen by a mass spectrometer
+	Monoisotopic MassType = iota
+
+	/// Average masses are weighted by the natural abundance of each isotope
+	Average
+)
+
+/// MonoisotopicMasses maps each amino acid onto the monoisotopic mass (in
+/// daltons) of its residue, i.e. the amino acid less a water molecule. U is
+/// selenocysteine and O pyrrolysine.
+var MonoisotopicMasses = map[rune]float64{
+	'A': 71.03711,
+	'C': 103.00919,
+	'D': 115.02694,
+	'E': 129.04259,
+	'F': 147.06841,
+	'G': 57.02146,
+	'H': 137.05891,
+	'I': 113.08406,
+	'K': 128.09496,
+	'L': 113.08406,
+	'M': 131.04049,
+	'N': 114.04293,
+	'O': 237.14773,
+	'P': 97.05276,
+	'Q': 128.05858,
+	'R': 156.10111,
+	'S': 87.03203,
+	'T': 101.04768,
+	'U': 150.95364,
+	'V': 99.06841,
+	'W': 186.07931,
+	'Y': 163.06333,
+}
+
+/// AverageMasses maps each amino acid onto the average mass (in daltons) of
+/// its residue
+var AverageMasses = map[rune]float64{
+	'A': 71.0788,
+	'C': 103.1388,
+	'D': 115.0886,
+	'E': 129.1155,
+	'F': 147.1766,
+	'G': 57.0519,
+	'H': 137.1411,
+	'I': 113.1594,
+	'K': 128.1741,
+	'L': 113.1594,
+	'M': 131.1926,
+	'N': 114.1038,
+	'O': 237.3018,
+	'P': 97.1167,
+	'Q': 128.1307,
+	'R': 156.1875,
+	'S': 87.0782,
+	'T': 101.1051,
+	'U': 150.0388,
+	'V': 99.1326,
+	'W': 186.2132,
+	'Y': 163.1760,
+}
+
+/// The masses of the water lost by each peptide bond (and so regained by
+/// the peptide as a whole), and of a proton.
+const (
+	WaterMonoisotopic = 18.01056
+	WaterAverage      = 18.01524
+	ProtonMass        = 1.007276
+)
+
+/// UnknownResidueError is returned when asked for the mass of something that
+/// isn't an amino acid. Offset is its index in the protein.
+type UnknownResidueError struct {
+	Residue rune
+	Offset  int
+}
+
+func (self UnknownResidueError) Error() string {
+	return fmt.Sprintf("Unknown residue %q at offset %d", self.Residue, self.Offset)
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Masses returns the residue mass table for a mass type
+func (self MassType) Masses() map[rune]float64 {
+	if self == Average {
+		return AverageMasses
+	}
+	return MonoisotopicMasses
+}
+
+/// Water returns the mass of a water molecule
+func (self MassType) Water() float64 {
+	if self == Average {
+		return WaterAverage
+	}
+	return WaterMonoisotopic
+}
+
+func (self MassType) String() string {
+	if self == Average {
+		return "average"
+	}
+	return "monoisotopic"
+}
+
+/// ResidueMass returns the mass of a single amino acid residue, or false if
+/// it isn't an amino acid.
+func ResidueMass(aa rune, t MassType) (float64, bool) {
+	mass, ok := t.Masses()[aa]
+	return mass, ok
+}
+
+/// ResiduesMass returns the total mass of the residues in a protein, not
+/// counting the water that completes the peptide.
+func ResiduesMass(protein string, t MassType) (float64, error) {
+	masses := t.Masses()
+	total := 0.0
+	for i, aa := range protein {
+		mass, ok := masses[aa]
+		if !ok {
+			return 0, UnknownResidueError{aa, i}
+		}
+		total += mass
+	}
+	return total, nil
+}
+
+/// PeptideMass returns the mass of a whole, uncharged peptide, i.e. its
+/// residues plus a water molecule.
+func PeptideMass(protein string, t MassType) (float64, error) {
+	mass, err := ResiduesMass(protein, t)
+	if err != nil {
+		return 0, err
+	}
+	return mass + t.Water(), nil
+}
+
+/// MassToCharge returns the m/z ratio of an ion formed by adding (or, for
+/// negative charges, removing) protons to a molecule of the given mass.
+/// Panics if charge is zero.
+func MassToCharge(mass float64, charge int) float64 {
+	if charge == 0 {
+		panic("protein: m/z is undefined for a neutral molecule")
+	}
+	return (mass + float64(charge)*ProtonMass) / math.Abs(float64(charge))
+}
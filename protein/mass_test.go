@@ -0,0 +1,77 @@
+package protein
+
+import (
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) < tolerance
+}
+
+func Test_TablesCoverTheSameResidues(t *testing.T) {
+	if len(MonoisotopicMasses) != len(AverageMasses) {
+		t.Fatalf("Expected tables of the same size, got %d and %d",
+			len(MonoisotopicMasses), len(AverageMasses))
+	}
+	for aa, mono := range MonoisotopicMasses {
+		avg, ok := AverageMasses[aa]
+		if !ok {
+			t.Errorf("No average mass for %c", aa)
+		} else if !approxEqual(mono, avg, 1) {
+			t.Errorf("Implausible masses for %c: %f vs %f", aa, mono, avg)
+		}
+	}
+}
+
+func Test_ResiduesMassMatchesRosalindSample(t *testing.T) {
+	mass, err := ResiduesMass("SKADYEK", Monoisotopic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if !approxEqual(mass, 821.392, 0.001) {
+		t.Errorf("Expected 821.392, got %f", mass)
+	}
+}
+
+func Test_PeptideMassIncludesWater(t *testing.T) {
+	tests := []struct {
+		protein  string
+		t        MassType
+		expected float64
+	}{
+		{"G", Monoisotopic, 75.03203},
+		{"G", Average, 75.0671},
+		{"", Monoisotopic, WaterMonoisotopic},
+		{"PEPTIDE", Monoisotopic, 799.35997},
+	}
+	for _, test := range tests {
+		mass, err := PeptideMass(test.protein, test.t)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err.Error())
+		}
+		if !approxEqual(mass, test.expected, 0.001) {
+			t.Errorf("Expected %s %s mass %f, got %f", test.t, test.protein, test.expected, mass)
+		}
+	}
+}
+
+func Test_UnknownResiduesAreAnError(t *testing.T) {
+	_, err := PeptideMass("PEPXTIDE", Monoisotopic)
+	if err != (UnknownResidueError{'X', 3}) {
+		t.Errorf("Expected UnknownResidueError, got %v", err)
+	}
+	if _, ok := ResidueMass('*', Average); ok {
+		t.Error("Expected no mass for a stop")
+	}
+}
+
+func Test_MassToCharge(t *testing.T) {
+	mass := 799.35997
+	tests := map[int]float64{1: 800.36725, 2: 400.68726, 3: 267.46060, -1: 798.35270}
+	for z, expected := range tests {
+		if mz := MassToCharge(mass, z); !approxEqual(mz, expected, 0.0001) {
+			t.Errorf("Expected m/z %f for charge %d, got %f", expected, z, mz)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package protein
+
+import (
+	"math"
+)
+
+/// The pKa values (EMBOSS scale) of the ionisable groups of a protein: its
+/// termini and side chains.
+const (
+	PKaNTerminus = 8.6
+	PKaCTerminus = 3.6
+)
+
+/// PKaPositive holds the pKa of each side chain that is positively charged
+/// when protonated
+var PKaPositive = map[rune]float64{
+	'K': 10.8,
+	'R': 12.5,
+	'H': 6.5,
+}
+
+/// PKaNegative holds the pKa of each side chain that is negatively charged
+/// when deprotonated
+var PKaNegative = map[rune]float64{
+	'D': 3.9,
+	'E': 4.1,
+	'C': 8.5,
+	'Y': 10.1,
+}
+
+/// KyteDoolittle is the Kyte & Doolittle hydropathy scale
+var KyteDoolittle = map[rune]float64{
+	'A': 1.8,
+	'C': 2.5,
+	'D': -3.5,
+	'E': -3.5,
+	'F': 2.8,
+	'G': -0.4,
+	'H': -3.2,
+	'I': 4.5,
+	'K': -3.9,
+	'L': 3.8,
+	'M': 1.9,
+	'N': -3.5,
+	'P': -1.6,
+	'Q': -3.5,
+	'R': -4.5,
+	'S': -0.8,
+	'T': -0.7,
+	'V': 4.2,
+	'W': -0.9,
+	'Y': -1.3,
+}
+
+/// The molar extinction coefficients (M^-1 cm^-1 at 280nm, Pace et al.) of
+/// the residues that absorb light.
+const (
+	ExtinctionTrp     = 5500
+	ExtinctionTyr     = 1490
+	ExtinctionCystine = 125
+)
+
+/// Properties summarises the physico-chemical properties of a protein
+type Properties struct {
+	Length int     `json:"length"`
+	Mass   float64 `json:"mass"`
+
+	/// IsoelectricPoint is the pH at which the protein has no net charge
+	IsoelectricPoint float64 `json:"isoelectric_point"`
+
+	/// PH is the pH the Charge was calculated at
+	PH     float64 `json:"ph"`
+	Charge float64 `json:"charge"`
+
+	/// Gravy is the grand average of hydropathy, on the Kyte & Doolittle
+	/// scale
+	Gravy float64 `json:"gravy"`
+
+	/// Extinction is the molar extinction coefficient assuming every pair of
+	/// cysteines forms a cystine; ExtinctionReduced assumes none do.
+	Extinction        int `json:"extinction"`
+	ExtinctionReduced int `json:"extinction_reduced"`
+
+	/// InstabilityIndex predicts stability in vitro; proteins scoring over 40
+	/// are likely to be unstable.
+	InstabilityIndex float64 `json:"instability_index"`
+
+	/// Composition counts each residue in the protein
+	Composition map[string]int `json:"composition"`
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// Analyze computes the properties of a protein, with its charge calculated
+/// at the given pH. The mass is the average mass of the whole peptide.
+func Analyze(protein string, pH float64) (Properties, error) {
+	mass, err := PeptideMass(protein, Average)
+	if err != nil {
+		return Properties{}, err
+	}
+
+	composition := map[string]int{}
+	length := 0
+	for _, aa := range protein {
+		composition[string(aa)]++
+		length++
+	}
+
+	extinction, reduced := ExtinctionCoefficients(protein)
+	return Properties{
+		Length:            length,
+		Mass:              mass,
+		IsoelectricPoint:  IsoelectricPoint(protein),
+		PH:                pH,
+		Charge:            NetCharge(protein, pH),
+		Gravy:             Gravy(protein),
+		Extinction:        extinction,
+		ExtinctionReduced: reduced,
+		InstabilityIndex:  InstabilityIndex(protein),
+		Composition:       composition,
+	}, nil
+}
+
+/// NetCharge estimates the charge of a protein at a given pH with the
+/// Henderson-Hasselbalch equation
+func NetCharge(protein string, pH float64) float64 {
+	positive := func(pKa float64) float64 { return 1 / (1 + math.Pow(10, pH-pKa)) }
+	negative := func(pKa float64) float64 { return 1 / (1 + math.Pow(10, pKa-pH)) }
+
+	if len(protein) == 0 {
+		return 0
+	}
+
+	charge := positive(PKaNTerminus) - negative(PKaCTerminus)
+	for _, aa := range protein {
+		if pKa, ok := PKaPositive[aa]; ok {
+			charge += positive(pKa)
+		} else if pKa, ok := PKaNegative[aa]; ok {
+			charge -= negative(pKa)
+		}
+	}
+	return charge
+}
+
+/// IsoelectricPoint finds the pH (to within 0.001) at which the protein's
+/// net charge is zero
+func IsoelectricPoint(protein string) float64 {
+	// the charge falls monotonically as the pH rises, so bisect
+	low, high := 0.0, 14.0
+	for high-low > 0.001 {
+		mid := (low + high) / 2
+		if NetCharge(protein, mid) > 0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
+
+/// Gravy returns the grand average of hydropathy: the mean Kyte & Doolittle
+/// hydropathy of the protein's residues. Residues not on the scale are
+/// ignored.
+func Gravy(protein string) float64 {
+	sum := 0.0
+	n := 0
+	for _, aa := range protein {
+		if h, ok := KyteDoolittle[aa]; ok {
+			sum += h
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+/// ExtinctionCoefficients returns the molar extinction coefficient of a
+/// protein at 280nm, first assuming its cysteines are all paired into
+/// cystines and then assuming they are all reduced.
+func ExtinctionCoefficients(protein string) (int, int) {
+	w, y, c := 0, 0, 0
+	for _, aa := range protein {
+		switch aa {
+		case 'W':
+			w++
+		case 'Y':
+			y++
+		case 'C':
+			c++
+		}
+	}
+	reduced := w*ExtinctionTrp + y*ExtinctionTyr
+	return reduced + (c/2)*ExtinctionCystine, reduced
+}
+
+/// InstabilityIndex computes the instability index of Guruprasad et al.,
+/// from the instability weights of each dipeptide in the protein.
+/// Dipeptides containing residues outside the standard twenty are ignored.
+func InstabilityIndex(protein string) float64 {
+	residues := []rune(protein)
+	if len(residues) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for i := 0; i+1 < len(residues); i++ {
+		sum += dipeptideInstability[residues[i]][residues[i+1]]
+	}
+	return 10 * sum / float64(len(residues))
+}
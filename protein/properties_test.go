@@ -0,0 +1,98 @@
+package protein
+
+import (
+	"testing"
+)
+
+func Test_InstabilityTableIsComplete(t *testing.T) {
+	for a := range KyteDoolittle {
+		row, ok := dipeptideInstability[a]
+		if !ok || len(row) != 20 {
+			t.Errorf("Expected 20 weights for %c, got %d", a, len(row))
+		}
+		for b := range KyteDoolittle {
+			if _, ok := row[b]; !ok {
+				t.Errorf("No weight for %c%c", a, b)
+			}
+		}
+	}
+}
+
+func Test_InstabilityIndex(t *testing.T) {
+	tests := map[string]float64{"": 0, "A": 0, "AC": 224.7, "ACA": 10 * (44.94 + 1) / 3, "AXA": 0}
+	for protein, expected := range tests {
+		if actual := InstabilityIndex(protein); !approxEqual(actual, expected, 1e-9) {
+			t.Errorf("Expected instability %f for %q, got %f", expected, protein, actual)
+		}
+	}
+}
+
+func Test_Gravy(t *testing.T) {
+	tests := map[string]float64{"": 0, "IR": 0, "AAV": 2.6, "AXV": 3}
+	for protein, expected := range tests {
+		if actual := Gravy(protein); !approxEqual(actual, expected, 1e-9) {
+			t.Errorf("Expected GRAVY %f for %q, got %f", expected, protein, actual)
+		}
+	}
+}
+
+func Test_ExtinctionCoefficients(t *testing.T) {
+	cystine, reduced := ExtinctionCoefficients("WWYCCCA")
+	if reduced != 12490 || cystine != 12615 {
+		t.Errorf("Expected 12615 and 12490, got %d and %d", cystine, reduced)
+	}
+}
+
+func Test_NetCharge(t *testing.T) {
+	// fully protonated at low pH, only the basic groups are charged
+	if c := NetCharge("KRHDE", 0); !approxEqual(c, 4, 0.01) {
+		t.Errorf("Expected charge 4 at pH 0, got %f", c)
+	}
+	if c := NetCharge("KRHDE", 14); !approxEqual(c, -3, 0.05) {
+		t.Errorf("Expected charge -3 at pH 14, got %f", c)
+	}
+	if c := NetCharge("", 7); c != 0 {
+		t.Errorf("Expected no charge for an empty protein, got %f", c)
+	}
+}
+
+func Test_IsoelectricPoint(t *testing.T) {
+	tests := []struct {
+		protein string
+		low     float64
+		high    float64
+	}{
+		{"KKKKRRRR", 11, 13},
+		{"DDDDEEEE", 2, 4},
+		{"G", 5.5, 6.5},
+	}
+	for _, test := range tests {
+		pI := IsoelectricPoint(test.protein)
+		if pI < test.low || pI > test.high {
+			t.Errorf("Expected pI of %s in [%f, %f], got %f", test.protein, test.low, test.high, pI)
+		}
+		if c := NetCharge(test.protein, pI); !approxEqual(c, 0, 0.01) {
+			t.Errorf("Expected no charge at the pI of %s, got %f", test.protein, c)
+		}
+	}
+}
+
+func Test_Analyze(t *testing.T) {
+	p, err := Analyze("MAWCC", 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p.Length != 5 || p.Composition["C"] != 2 || p.Composition["M"] != 1 {
+		t.Errorf("Unexpected length or composition: %d %v", p.Length, p.Composition)
+	}
+	if p.Extinction != 5625 || p.ExtinctionReduced != 5500 {
+		t.Errorf("Unexpected extinction coefficients: %d %d", p.Extinction, p.ExtinctionReduced)
+	}
+	if p.PH != 7 || p.Charge != NetCharge("MAWCC", 7) {
+		t.Errorf("Unexpected charge %f at pH %f", p.Charge, p.PH)
+	}
+
+	if _, err := Analyze("MAB", 7); err != (UnknownResidueError{'B', 2}) {
+		t.Errorf("Expected UnknownResidueError, got %v", err)
+	}
+}
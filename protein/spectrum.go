@@ -0,0 +1,187 @@
+package protein
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+/// DefaultTolerance is a mass tolerance (in daltons) tight enough to tell
+/// every pair of residues apart, apart from the isobaric I and L, for
+/// spectra given to several decimal places.
+const DefaultTolerance = 0.01
+
+/// UnmatchedMassError is returned when a mass difference in a spectrum
+/// doesn't match any residue. Offset is the index in the spectrum of the
+/// heavier of the two masses.
+type UnmatchedMassError struct {
+	Mass   float64
+	Offset int
+}
+
+func (self UnmatchedMassError) Error() string {
+	return fmt.Sprintf("No residue of mass %f at offset %d", self.Mass, self.Offset)
+}
+
+/// SpectrumError describes a spectrum that can't be interpreted
+type SpectrumError string
+
+func (self SpectrumError) Error() string {
+	return fmt.Sprintf("Invalid spectrum: %s", string(self))
+}
+
+/// Peak is a shift found by spectral convolution, and the number of pairs
+/// of masses it separates
+type Peak struct {
+	Shift float64
+	Count int
+}
+
+/// residuesByType lists the residues in each mass table alphabetically, so
+/// that MatchResidue can break ties without sorting on every call
+var residuesByType = map[MassType][]rune{
+	Monoisotopic: sortedResidues(MonoisotopicMasses),
+	Average:      sortedResidues(AverageMasses),
+}
+
+// ----------------------------------------------------------------------------
+//
+// ----------------------------------------------------------------------------
+
+/// MatchResidue finds the residue whose mass is closest to the given mass,
+/// if any is within tolerance. Ties (e.g. I and L) go to the first residue
+/// alphabetically.
+func MatchResidue(mass, tolerance float64, t MassType) (rune, bool) {
+	masses := t.Masses()
+	best := rune(0)
+	bestError := math.Inf(1)
+	for _, aa := range residuesByType[t] {
+		if e := math.Abs(masses[aa] - mass); e <= tolerance && e < bestError {
+			best, bestError = aa, e
+		}
+	}
+	return best, best != 0
+}
+
+/// FromPrefixSpectrum infers a protein from its prefix spectrum: the masses
+/// of each of its prefixes, in order. Each difference between consecutive
+/// masses must match a residue to within tolerance.
+func FromPrefixSpectrum(spectrum []float64, tolerance float64, t MassType) (string, error) {
+	result := make([]rune, 0, len(spectrum))
+	for i := 1; i < len(spectrum); i++ {
+		delta := spectrum[i] - spectrum[i-1]
+		aa, ok := MatchResidue(delta, tolerance, t)
+		if !ok {
+			return "", UnmatchedMassError{delta, i}
+		}
+		result = append(result, aa)
+	}
+	return string(result), nil
+}
+
+/// Convolution computes the spectral convolution of two spectra: the
+/// multiset of every difference a[i] - b[j] (their Minkowski difference).
+/// Differences within tolerance of each other are counted as the same shift,
+/// reported as their mean. Peaks are ordered by count, most common first,
+/// then by shift.
+func Convolution(a, b []float64, tolerance float64) []Peak {
+	diffs := make([]float64, 0, len(a)*len(b))
+	for _, x := range a {
+		for _, y := range b {
+			diffs = append(diffs, x-y)
+		}
+	}
+	sort.Float64s(diffs)
+
+	result := []Peak{}
+	for i := 0; i < len(diffs); {
+		j := i
+		sum := 0.0
+		for ; j < len(diffs) && diffs[j]-diffs[i] <= tolerance; j++ {
+			sum += diffs[j]
+		}
+		result = append(result, Peak{Shift: sum / float64(j-i), Count: j - i})
+		i = j
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	return result
+}
+
+/// FromIons reconstructs a peptide from the masses of the b and y ions
+/// produced by breaking it at every peptide bond, given the mass of the
+/// whole (parent) peptide. Each break yields a complementary pair of ions
+/// whose masses sum to the parent mass, so a peptide of n residues has
+/// 2(n+1) ions. The peptide is read from a chain of n+1 ions, each with its
+/// complement present, whose successive differences are residue masses.
+func FromIons(parent float64, ions []float64, tolerance float64, t MassType) (string, error) {
+	if len(ions) < 2 || len(ions)%2 != 0 {
+		return "", SpectrumError(fmt.Sprintf("expected an even number of ions, got %d", len(ions)))
+	}
+	n := len(ions)/2 - 1
+
+	sorted := append([]float64{}, ions...)
+	sort.Float64s(sorted)
+
+	// only ions whose complement is also present can be part of the chain
+	paired := []float64{}
+	for _, mass := range sorted {
+		if containsMass(sorted, parent-mass, tolerance) {
+			paired = append(paired, mass)
+		}
+	}
+
+	heaviest := maxResidueMass(t)
+	var chain func(from int, peptide []rune) []rune
+	chain = func(from int, peptide []rune) []rune {
+		if len(peptide) == n {
+			return peptide
+		}
+		for next := from + 1; next < len(paired); next++ {
+			delta := paired[next] - paired[from]
+			if delta > heaviest+tolerance {
+				break
+			}
+			if aa, ok := MatchResidue(delta, tolerance, t); ok {
+				if result := chain(next, append(peptide, aa)); result != nil {
+					return result
+				}
+			}
+		}
+		return nil
+	}
+
+	for start := range paired {
+		if peptide := chain(start, make([]rune, 0, n)); peptide != nil {
+			return string(peptide), nil
+		}
+	}
+	return "", SpectrumError("no chain of residues through the ions")
+}
+
+/// containsMass checks whether a sorted list of masses contains one within
+/// tolerance of the given mass
+func containsMass(sorted []float64, mass, tolerance float64) bool {
+	i := sort.SearchFloat64s(sorted, mass-tolerance)
+	return i < len(sorted) && sorted[i] <= mass+tolerance
+}
+
+func sortedResidues(masses map[rune]float64) []rune {
+	result := make([]rune, 0, len(masses))
+	for aa := range masses {
+		result = append(result, aa)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+/// maxResidueMass returns the mass of the heaviest residue
+func maxResidueMass(t MassType) float64 {
+	result := 0.0
+	for _, mass := range t.Masses() {
+		result = max(result, mass)
+	}
+	return result
+}
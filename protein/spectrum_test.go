@@ -0,0 +1,94 @@
+package protein
+
+import (
+	"testing"
+)
+
+func Test_MatchResidue(t *testing.T) {
+	tests := map[float64]rune{57.02146: 'G', 57.025: 'G', 113.08406: 'I', 128.09496: 'K', 128.05858: 'Q'}
+	for mass, expected := range tests {
+		if aa, ok := MatchResidue(mass, DefaultTolerance, Monoisotopic); !ok || aa != expected {
+			t.Errorf("Expected %f to match %c, got %c", mass, expected, aa)
+		}
+	}
+	if _, ok := MatchResidue(60, DefaultTolerance, Monoisotopic); ok {
+		t.Error("Expected 60 Da not to match anything")
+	}
+}
+
+func Test_FromPrefixSpectrumMatchesRosalindSample(t *testing.T) {
+	spectrum := []float64{3524.8542, 3710.9335, 3841.974, 3970.0326, 4057.0646}
+	p, err := FromPrefixSpectrum(spectrum, DefaultTolerance, Monoisotopic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "WMQS" {
+		t.Errorf("Expected \"WMQS\", got \"%s\"", p)
+	}
+}
+
+func Test_FromPrefixSpectrumReportsGaps(t *testing.T) {
+	_, err := FromPrefixSpectrum([]float64{100, 157.02146, 217.02146}, DefaultTolerance, Monoisotopic)
+	if e, ok := err.(UnmatchedMassError); !ok || e.Offset != 2 {
+		t.Errorf("Expected UnmatchedMassError at 2, got %v", err)
+	}
+}
+
+func Test_ConvolutionMatchesRosalindSample(t *testing.T) {
+	a := []float64{186.07931, 287.12699, 548.20532, 580.18077, 681.22845, 706.27446,
+		782.27613, 968.35544, 968.35544}
+	b := []float64{101.04768, 158.06914, 202.09536, 318.09979, 419.14747, 463.17369}
+
+	peaks := Convolution(a, b, 0.00001)
+	if len(peaks) == 0 {
+		t.Fatal("Expected some peaks")
+	}
+	if peaks[0].Count != 3 || !approxEqual(peaks[0].Shift, 85.03163, 0.00001) {
+		t.Errorf("Expected 3 at 85.03163, got %d at %f", peaks[0].Count, peaks[0].Shift)
+	}
+
+	total := 0
+	for _, peak := range peaks {
+		total += peak.Count
+	}
+	if total != len(a)*len(b) {
+		t.Errorf("Expected %d differences, got %d", len(a)*len(b), total)
+	}
+}
+
+func Test_ConvolutionGroupsWithinTolerance(t *testing.T) {
+	peaks := Convolution([]float64{10, 20.01, 30.02}, []float64{0}, 0.1)
+	if len(peaks) != 3 {
+		t.Fatalf("Expected 3 peaks, got %v", peaks)
+	}
+
+	// 10 - 0, 20 - 9.99 and 10.02 - 0 are all the same shift
+	peaks = Convolution([]float64{10, 10.02, 20}, []float64{0, 9.99}, 0.05)
+	if peaks[0].Count != 3 || !approxEqual(peaks[0].Shift, 10.01, 1e-9) {
+		t.Errorf("Expected 3 at 10.01, got %v", peaks[0])
+	}
+}
+
+func Test_FromIonsMatchesRosalindSample(t *testing.T) {
+	parent := 1988.21104821
+	ions := []float64{610.391039105, 738.485999105, 766.492149105, 863.544909105,
+		867.528589105, 992.587499105, 995.623549105, 1120.6824591, 1124.6661391,
+		1221.7188991, 1249.7250491, 1377.8200091}
+
+	p, err := FromIons(parent, ions, DefaultTolerance, Monoisotopic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err.Error())
+	}
+	if p != "KEKEP" {
+		t.Errorf("Expected \"KEKEP\", got \"%s\"", p)
+	}
+}
+
+func Test_FromIonsReportsBadSpectra(t *testing.T) {
+	if _, err := FromIons(100, []float64{1, 2, 3}, DefaultTolerance, Monoisotopic); err == nil {
+		t.Error("Expected an error for an odd number of ions")
+	}
+	if _, err := FromIons(100, []float64{10, 90, 30, 70}, DefaultTolerance, Monoisotopic); err == nil {
+		t.Error("Expected an error for ions with no residue between them")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/tcsc/rosalind/fasta"
+	"github.com/tcsc/rosalind/protein"
+	"io"
+	"os"
+	"strings"
+)
+
+/// residues lists the amino acids reported in the TSV composition columns
+const residues = "ACDEFGHIKLMNPQRSTVWY"
+
+type args struct {
+	filename string
+	format   string
+	pH       float64
+}
+
+func parseArgs() args {
+	result := args{}
+	flag.StringVar(&result.format, "format", "tsv", "Output format: tsv or json")
+	flag.Float64Var(&result.pH, "ph", 7.0, "pH to calculate the net charge at")
+	flag.Parse()
+
+	if result.format != "tsv" && result.format != "json" {
+		fmt.Fprintf(os.Stderr, "Unknown format: %s\n", result.format)
+		os.Exit(1)
+	}
+
+	result.filename = flag.Arg(0)
+	return result
+}
+
+type record struct {
+	Name string `json:"name"`
+	protein.Properties
+}
+
+func writeTSVHeader() {
+	columns := []string{"name", "length", "mass", "isoelectric_point", "ph", "charge",
+		"gravy", "extinction", "extinction_reduced", "instability_index"}
+	for _, aa := range residues {
+		columns = append(columns, string(aa))
+	}
+	fmt.Println(strings.Join(columns, "\t"))
+}
+
+func writeTSV(r record) {
+	fmt.Printf("%s\t%d\t%.4f\t%.3f\t%.2f\t%.3f\t%.4f\t%d\t%d\t%.2f",
+		r.Name, r.Length, r.Mass, r.IsoelectricPoint, r.PH, r.Charge,
+		r.Gravy, r.Extinction, r.ExtinctionReduced, r.InstabilityIndex)
+	for _, aa := range residues {
+		fmt.Printf("\t%d", r.Composition[string(aa)])
+	}
+	fmt.Println()
+}
+
+func main() {
+	args := parseArgs()
+
+	var input io.Reader = os.Stdin
+	if args.filename != "" {
+		file, err := fasta.Open(args.filename)
+		if err != nil {
+			panic(err)
+		}
+		input = file
+	}
+	records := fasta.Read(input)
+
+	results := []record{}
+	for str := range records {
+		if str.Error != nil {
+			panic(str.Error)
+		}
+
+		// a translated protein may end with a stop
+		properties, err := protein.Analyze(strings.TrimSuffix(str.Sequence, "*"), args.pH)
+		if err != nil {
+			panic(fmt.Errorf("%s: %s", str.Name, err))
+		}
+		results = append(results, record{str.Name, properties})
+	}
+
+	if args.format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	writeTSVHeader()
+	for _, r := range results {
+		writeTSV(r)
+	}
+}
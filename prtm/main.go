@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/tcsc/rosalind/fasta"
+	"github.com/tcsc/rosalind/protein"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type args struct {
+	filename string
+	average  bool
+	table    bool
+	charges  []int
+}
+
+func parseArgs() args {
+	result := args{}
+	charges := ""
+	flag.BoolVar(&result.average, "average", false,
+		"Use average rather than monoisotopic masses")
+	flag.BoolVar(&result.table, "table", false,
+		"Print a table of residue and peptide masses rather than the Rosalind answer")
+	flag.StringVar(&charges, "z", "", "Comma-separated charge states to print m/z for (implies -table)")
+	flag.Parse()
+
+	for _, z := range strings.Split(charges, ",") {
+		if z = strings.TrimSpace(z); z == "" {
+			continue
+		}
+		charge, err := strconv.Atoi(z)
+		if err != nil || charge == 0 {
+			fmt.Fprintf(os.Stderr, "Invalid charge state: %s\n", z)
+			os.Exit(1)
+		}
+		result.charges = append(result.charges, charge)
+	}
+
+	result.table = result.table || len(result.charges) > 0
+	result.filename = flag.Arg(0)
+	return result
+}
+
+/// readProteins reads named proteins from either FASTA or plain text with one
+/// protein per line, in which case they are named by line number. Either may
+/// be gzip compressed.
+func readProteins(reader io.Reader) <-chan fasta.String {
+	decompressed, err := fasta.Decompress(reader)
+	var data []byte
+	if err == nil {
+		data, err = ioutil.ReadAll(decompressed)
+	}
+	if err != nil {
+		ch := make(chan fasta.String, 1)
+		ch <- fasta.String{Error: err}
+		close(ch)
+		return ch
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '>' {
+		return fasta.Read(bytes.NewReader(data))
+	}
+
+	ch := make(chan fasta.String, 2)
+	go func() {
+		defer close(ch)
+		s := bufio.NewScanner(bytes.NewReader(data))
+		line := 0
+		for s.Scan() {
+			line++
+			if text := strings.TrimSpace(s.Text()); text != "" {
+				ch <- fasta.String{Name: strconv.Itoa(line), Sequence: text}
+			}
+		}
+	}()
+	return ch
+}
+
+func main() {
+	args := parseArgs()
+
+	massType := protein.Monoisotopic
+	if args.average {
+		massType = protein.Average
+	}
+
+	var input io.Reader = os.Stdin
+	if args.filename != "" {
+		file, err := fasta.Open(args.filename)
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	if args.table {
+		header := "name\tresidues\tmass"
+		for _, z := range args.charges {
+			header += fmt.Sprintf("\tmz_%d", z)
+		}
+		fmt.Println(header)
+	}
+
+	for str := range readProteins(input) {
+		if str.Error != nil {
+			panic(str.Error)
+		}
+
+		residues, err := protein.ResiduesMass(str.Sequence, massType)
+		if err != nil {
+			panic(fmt.Errorf("%s: %s", str.Name, err))
+		}
+		// Rosalind wants just the total of the residue masses
+		if !args.table {
+			fmt.Printf("%.3f\n", residues)
+			continue
+		}
+
+		mass := residues + massType.Water()
+
+		fmt.Printf("%s\t%.5f\t%.5f", str.Name, residues, mass)
+		for _, z := range args.charges {
+			fmt.Printf("\t%.5f", protein.MassToCharge(mass, z))
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"github.com/tcsc/rosalind/codon"
+	"github.com/tcsc/rosalind/fasta"
+	"io"
+	"os"
+)
+
+func main() {
+	var input io.Reader = os.Stdin
+	if len(os.Args) > 1 {
+		file, err := fasta.Open(os.Args[1])
+		if err != nil {
+			panic(err)
+		}
+		input = file
+	}
+	records := fasta.Read(input)
+
+	// the first record is the gene, and everything after it an intron
+	gene := ""
+	introns := []string{}
+	first := true
+	for str := range records {
+		if str.Error != nil {
+			panic(str.Error)
+		}
+
+		if first {
+			gene = str.Sequence
+			first = false
+		} else {
+			introns = append(introns, str.Sequence)
+		}
+	}
+
+	protein, err := codon.SpliceAndTranslate(gene, introns, codon.Options{})
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%s\n", protein)
+}